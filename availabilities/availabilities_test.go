@@ -1,49 +1,37 @@
 package availabilities_test
 
 import (
-	"bufio"
 	"context"
-	"fmt"
-	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"path"
-	"strings"
 	"testing"
 
 	"github.com/meplato/store2-go-client/v2/availabilities"
+	"github.com/meplato/store2-go-client/v2/storetest"
 )
 
 func getService(responseFile string) (*availabilities.Service, *httptest.Server, error) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slurp, err := os.ReadFile(path.Join("testdata", responseFile))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(string(slurp))), r)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer res.Body.Close()
-		bs, err := io.ReadAll(res.Body)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(res.StatusCode)
-		fmt.Fprint(w, string(bs))
-	}))
+	ts := storetest.ReplayServer(path.Join("testdata", responseFile))
+
+	service, err := availabilities.NewFromEnv()
+	if err != nil {
+		return service, nil, err
+	}
+	service.BaseURL = ts.URL
+	return service, ts, nil
+}
+
+func getServiceWithRoutes(route func(r *http.Request) string) (*availabilities.Service, *httptest.Server, error) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		return path.Join("testdata", route(r))
+	})
 
-	service, err := availabilities.New(http.DefaultClient)
+	service, err := availabilities.NewFromEnv()
 	if err != nil {
 		return service, nil, err
 	}
 	service.BaseURL = ts.URL
-	service.User = os.Getenv("STORE2_USER")
-	service.Password = os.Getenv("STORE2_PASSWORD")
 	return service, ts, nil
 }
 
@@ -0,0 +1,152 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package availabilities
+
+import (
+	"context"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// DefaultBatchWorkers is the number of concurrent requests used by
+// BatchDelete unless configured otherwise.
+const DefaultBatchWorkers = 4
+
+func (s *Service) BatchDelete() *BatchDeleteService {
+	return NewBatchDeleteService(s)
+}
+
+// BatchDeleteItem is a single availability to delete in a batch, along
+// with the outcome of deleting it.
+type BatchDeleteItem struct {
+	// Index is the position of the item in the batch.
+	Index int
+	// Spn of the product.
+	Spn string
+	// Region restricts the deletion to availabilities in the given
+	// country/region, if set.
+	Region string
+	// ZipCode restricts the deletion to availabilities with the given zip
+	// code, if set.
+	ZipCode string
+	// Response is the response of the server if the deletion has been
+	// accepted.
+	Response *DeleteResponse
+	// Err is the error if the deletion failed.
+	Err error
+}
+
+// BatchDeleteResponse is the outcome of deleting the availabilities of
+// multiple products at once.
+type BatchDeleteResponse struct {
+	// Items contains the outcome for every item, in the order of the
+	// batch.
+	Items []*BatchDeleteItem
+}
+
+// Failed returns the items whose deletion failed.
+func (r *BatchDeleteResponse) Failed() []*BatchDeleteItem {
+	var failed []*BatchDeleteItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// BatchDelete deletes the availability information of several products,
+// e.g. of all products of a discontinued supplier. It issues one Delete
+// per item with bounded concurrency and reports the outcome for every
+// item. Like Delete, the deletion is asynchronous: a successful item means
+// that the server has accepted the deletion, not that it has completed.
+type BatchDeleteService struct {
+	s       *Service
+	hdr_    map[string]interface{}
+	items   []*BatchDeleteItem
+	workers int
+}
+
+// NewBatchDeleteService creates a new instance of BatchDeleteService.
+func NewBatchDeleteService(s *Service) *BatchDeleteService {
+	rs := &BatchDeleteService{s: s, hdr_: make(map[string]interface{}), workers: DefaultBatchWorkers}
+	return rs
+}
+
+// SPNs adds the products whose availabilities are deleted in all regions
+// and zip codes.
+func (s *BatchDeleteService) SPNs(spns ...string) *BatchDeleteService {
+	for _, spn := range spns {
+		s.Add(spn, "", "")
+	}
+	return s
+}
+
+// Add adds a product whose availabilities are deleted. If region or
+// zipCode are not empty, only the availabilities in that region or with
+// that zip code are deleted.
+func (s *BatchDeleteService) Add(spn, region, zipCode string) *BatchDeleteService {
+	s.items = append(s.items, &BatchDeleteItem{Index: len(s.items), Spn: spn, Region: region, ZipCode: zipCode})
+	return s
+}
+
+// Workers is the maximum number of concurrent requests (default 4).
+func (s *BatchDeleteService) Workers(workers int) *BatchDeleteService {
+	s.workers = workers
+	return s
+}
+
+// WithAuth overrides the user and password of the service for the
+// requests of this operation only.
+func (s *BatchDeleteService) WithAuth(user, password string) *BatchDeleteService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// Do executes the operation. Errors of single items are reported in the
+// items of the response. Do only returns an error if ctx is done before
+// all items have been sent. In that case, it returns the response
+// together with the context error, and the items that have not been
+// sent report the context error.
+func (s *BatchDeleteService) Do(ctx context.Context) (*BatchDeleteResponse, error) {
+	ret := &BatchDeleteResponse{Items: make([]*BatchDeleteItem, len(s.items))}
+	for i, item := range s.items {
+		c := *item
+		ret.Items[i] = &c
+	}
+	sent := make([]bool, len(ret.Items))
+	err := meplatoapi.ForEach(ctx, len(ret.Items), s.workers, func(ctx context.Context, i int) {
+		sent[i] = true
+		item := ret.Items[i]
+		del := s.s.Delete().Spn(item.Spn)
+		if item.Region != "" {
+			del = del.Region(item.Region)
+		}
+		if item.ZipCode != "" {
+			del = del.ZipCode(item.ZipCode)
+		}
+		for k, v := range s.hdr_ {
+			del.hdr_[k] = v
+		}
+		item.Response, item.Err = del.Do(ctx)
+	})
+	if err != nil {
+		for i, item := range ret.Items {
+			if !sent[i] {
+				item.Err = err
+			}
+		}
+		return ret, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,113 @@
+package availabilities_test
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/availabilities"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestAvailabilitiesBatchDelete(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	var current, max int
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		current++
+		if current > max {
+			max = current
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		current--
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "/unknown/") {
+			return path.Join("testdata", "availabilities.delete.not_found")
+		}
+		return path.Join("testdata", "availabilities.delete.success")
+	})
+	defer ts.Close()
+	service, err := availabilities.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	res, err := service.BatchDelete().
+		SPNs("1000", "unknown", "2000").
+		Add("3000", "DE", "").
+		Add("4000", "DE", "12345").
+		Workers(2).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 5 {
+		t.Fatalf("expected %d items; got: %d", 5, len(res.Items))
+	}
+	for i, item := range res.Items {
+		if item.Index != i {
+			t.Errorf("expected item %d to have index %d; got: %d", i, i, item.Index)
+		}
+		if item.Spn == "unknown" {
+			continue
+		}
+		if item.Err != nil || item.Response == nil || item.Response.Kind != "store#availabilities/deleteResponse" {
+			t.Errorf("expected item %d (%s) to succeed; got: %+v", i, item.Spn, item)
+		}
+	}
+	failed := res.Failed()
+	if len(failed) != 1 || failed[0].Spn != "unknown" || failed[0].Err == nil {
+		t.Fatalf("expected SPN unknown to fail; got: %+v", failed)
+	}
+	if max > 2 {
+		t.Errorf("expected at most %d concurrent requests; got: %d", 2, max)
+	}
+
+	sort.Strings(requests)
+	want := []string{
+		"DELETE /products/1000/availabilities?",
+		"DELETE /products/2000/availabilities?",
+		"DELETE /products/3000/availabilities?region=DE",
+		"DELETE /products/4000/availabilities?region=DE&zipCode=12345",
+		"DELETE /products/unknown/availabilities?",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected requests:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(requests, "\n"))
+	}
+}
+
+func TestAvailabilitiesBatchDeleteCanceled(t *testing.T) {
+	service, ts, err := getService("availabilities.delete.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := service.BatchDelete().SPNs("1000", "2000").Do(ctx)
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+	if res == nil {
+		t.Fatal("expected partial response; got: nil")
+	}
+	if failed := res.Failed(); len(failed) != 2 {
+		t.Fatalf("expected %d failed items; got: %d", 2, len(failed))
+	}
+	for _, item := range res.Items {
+		if item.Err != context.Canceled {
+			t.Errorf("expected %s to report %v; got: %v", item.Spn, context.Canceled, item.Err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package availabilities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by GetOne if no availability matches the
+// MPCC, region, and zip code.
+var ErrNotFound = errors.New("availabilities: not found")
+
+// GetOne executes the operation and returns the single availability that
+// matches the MPCC, region, and zip code. Region is compared
+// case-insensitively. It returns ErrNotFound if no entry matches, and an
+// error if more than one entry matches, e.g. because region or zip code
+// have not been set.
+func (s *GetService) GetOne(ctx context.Context) (*Availability, error) {
+	res, err := s.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mpcc, _ := s.opt_["mpcc"].(string)
+	region, _ := s.opt_["region"].(string)
+	zipCode, _ := s.opt_["zipCode"].(string)
+	var found *Availability
+	for _, item := range res.Items {
+		if item == nil {
+			continue
+		}
+		if mpcc != "" && item.Mpcc != mpcc {
+			continue
+		}
+		if region != "" && !strings.EqualFold(item.Region, region) {
+			continue
+		}
+		if zipCode != "" && item.ZipCode != zipCode {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("availabilities: more than one availability found for SPN %q; specify region and zip code", s.spn)
+		}
+		found = item
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
@@ -0,0 +1,34 @@
+package availabilities_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/availabilities"
+)
+
+func TestAvailabilitiesGetOne(t *testing.T) {
+	service, ts, err := getService("availabilities.get.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	a, err := service.Get().Spn("1234").Region("dk").ZipCode("05109").GetOne(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Region != "DK" || a.ZipCode != "05109" {
+		t.Fatalf("expected availability for DK/05109; got: %s/%s", a.Region, a.ZipCode)
+	}
+
+	_, err = service.Get().Spn("1234").Region("DE").ZipCode("99999").GetOne(context.Background())
+	if err != availabilities.ErrNotFound {
+		t.Fatalf("expected %v; got: %v", availabilities.ErrNotFound, err)
+	}
+
+	_, err = service.Get().Spn("1234").GetOne(context.Background())
+	if err == nil || err == availabilities.ErrNotFound {
+		t.Fatalf("expected error for ambiguous result; got: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package availabilities_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/availabilities"
+)
+
+func TestAvailabilitiesGetMpcc(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.URL.Query().Get("mpcc") == "meplato" {
+			return "availabilities.get.mpcc"
+		}
+		return "availabilities.get.merchants"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Get().Spn("1234").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 3 {
+		t.Fatalf("expected %d items without filter; got: %d", 3, len(res.Items))
+	}
+
+	res, err = service.Get().Spn("1234").Mpcc("meplato").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected %d items with filter; got: %d", 2, len(res.Items))
+	}
+	for _, item := range res.Items {
+		if item.Mpcc != "meplato" {
+			t.Errorf("expected mpcc %q; got: %q", "meplato", item.Mpcc)
+		}
+	}
+}
+
+func TestAvailabilitiesGetOneMpcc(t *testing.T) {
+	service, ts, err := getService("availabilities.get.merchants")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	// The region and zip code alone match availabilities of two merchants
+	_, err = service.Get().Spn("1234").Region("DE").ZipCode("04109").GetOne(context.Background())
+	if err == nil || err == availabilities.ErrNotFound {
+		t.Fatalf("expected error for ambiguous result; got: %v", err)
+	}
+
+	a, err := service.Get().Spn("1234").Mpcc("abc-elektronik").Region("DE").ZipCode("04109").GetOne(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Mpcc != "abc-elektronik" {
+		t.Fatalf("expected mpcc %q; got: %q", "abc-elektronik", a.Mpcc)
+	}
+}
+
+func TestAvailabilitiesUpsertMpcc(t *testing.T) {
+	var got availabilities.UpsertRequest
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(data, &got)
+		}
+		if err != nil {
+			t.Errorf("expected JSON body; got: %v", err)
+		}
+		return "availabilities.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	quantity := 5.0
+	_, err = service.Upsert().Spn("1234").Availability(&availabilities.UpsertRequest{
+		Message:  "in stock",
+		Mpcc:     "abc-elektronik",
+		Quantity: &quantity,
+		Region:   "DE",
+		ZipCode:  "04109",
+	}).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Mpcc != "abc-elektronik" {
+		t.Fatalf("expected mpcc %q to be sent; got: %q", "abc-elektronik", got.Mpcc)
+	}
+}
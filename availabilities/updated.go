@@ -0,0 +1,92 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package availabilities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// updatedLayouts are the date formats recognized by ParseUpdated.
+var updatedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"2006/1/2",
+	"02.01.2006",
+	"2.1.2006",
+	"01/2006",
+	"1/2006",
+	"2006-01",
+}
+
+// ParseUpdated parses the free-form Updated field of an availability.
+// Surrounding whitespace is ignored. It recognizes the following formats:
+//
+//	2022-10-12T08:30:00Z   RFC 3339
+//	2022-10-12T08:30:00    ISO 8601 without time zone (UTC)
+//	2022-10-12             ISO 8601 date
+//	2022/10/12, 2022/1/2   year/month/day
+//	12.10.2022, 2.1.2022   day.month.year
+//	10/2022, 1/2022        month/year, i.e. the first day of the month
+//	2022-10                year-month, i.e. the first day of the month
+//	Q4/2022, Q4 2022       quarter, i.e. the first day of the quarter
+//	2022/Q4, 2022-Q4       quarter, i.e. the first day of the quarter
+//
+// Values without a time zone are returned in UTC. If s cannot be parsed,
+// e.g. because it is a quarter like "Q5/2022" or free text like "soon",
+// ok is false and callers should fall back to displaying s as is.
+func ParseUpdated(s string) (t time.Time, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if t, ok := parseQuarter(s); ok {
+		return t, true
+	}
+	for _, layout := range updatedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseQuarter parses quarters like Q4/2022, Q4 2022, Q4-2022, 2022/Q4,
+// and 2022-Q4, case-insensitively.
+func parseQuarter(s string) (time.Time, bool) {
+	parts := strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return r == '/' || r == '-' || r == ' '
+	})
+	if len(parts) != 2 {
+		return time.Time{}, false
+	}
+	q, y := parts[0], parts[1]
+	if strings.HasPrefix(y, "Q") {
+		q, y = y, q
+	}
+	if len(q) != 2 || q[0] != 'Q' || q[1] < '1' || q[1] > '4' {
+		return time.Time{}, false
+	}
+	if len(y) != 4 {
+		return time.Time{}, false
+	}
+	year, err := strconv.Atoi(y)
+	if err != nil {
+		return time.Time{}, false
+	}
+	month := time.Month(3*int(q[1]-'1') + 1)
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), true
+}
@@ -0,0 +1,44 @@
+package availabilities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/availabilities"
+)
+
+func TestParseUpdated(t *testing.T) {
+	tests := []struct {
+		In   string
+		Want time.Time
+		OK   bool
+	}{
+		{"2022-10-12T08:30:00Z", time.Date(2022, 10, 12, 8, 30, 0, 0, time.UTC), true},
+		{"2022-10-12T08:30:00", time.Date(2022, 10, 12, 8, 30, 0, 0, time.UTC), true},
+		{"2022-10-12", time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC), true},
+		{"2022/10/12", time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC), true},
+		{" 2022/1/2 ", time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"12.10.2022", time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC), true},
+		{"10/2022", time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2022-10", time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), true},
+		{"Q4/2022", time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), true},
+		{"q1 2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2022-Q2", time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2022/Q3", time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), true},
+		{"Q5/2022", time.Time{}, false},
+		{"Q4/22", time.Time{}, false},
+		{"Q4", time.Time{}, false},
+		{"2022/13/01", time.Time{}, false},
+		{"soon", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for i, test := range tests {
+		got, ok := availabilities.ParseUpdated(test.In)
+		if ok != test.OK {
+			t.Errorf("#%d: expected ok=%v for %q; got: %v", i, test.OK, test.In, ok)
+		}
+		if !got.Equal(test.Want) {
+			t.Errorf("#%d: expected %v for %q; got: %v", i, test.Want, test.In, got)
+		}
+	}
+}
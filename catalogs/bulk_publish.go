@@ -0,0 +1,184 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// DefaultBulkPublishWorkers is the number of catalogs published
+// concurrently by BulkPublishService unless configured otherwise.
+const DefaultBulkPublishWorkers = 4
+
+// ErrPublishCanceled is reported by BulkPublishService for catalogs whose
+// publishing process has been canceled on the server.
+var ErrPublishCanceled = errors.New("catalogs: publishing has been canceled")
+
+func (s *Service) BulkPublish() *BulkPublishService {
+	return NewBulkPublishService(s)
+}
+
+// BulkPublishItem is the outcome of publishing a single catalog.
+type BulkPublishItem struct {
+	// PIN of the catalog.
+	PIN string
+	// Status is the last publish status retrieved for the catalog. It is
+	// nil if publishing could not be started.
+	Status *PublishStatusResponse
+	// Err is the error if publishing failed or has been canceled.
+	Err error
+}
+
+// BulkPublishResponse is the outcome of publishing several catalogs.
+type BulkPublishResponse struct {
+	// Items contains the outcome for every catalog, in the order of the
+	// PINs.
+	Items []*BulkPublishItem
+}
+
+// Failed returns the items whose publishing failed.
+func (r *BulkPublishResponse) Failed() []*BulkPublishItem {
+	var failed []*BulkPublishItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// BulkPublishService publishes several catalogs, e.g. after a nightly
+// import. It starts publishing with bounded concurrency and polls the
+// publish status of each catalog until it is done. Like BatchUpsert in
+// the products package, it does not stop at the first error but reports
+// the outcome for every catalog.
+type BulkPublishService struct {
+	s       *Service
+	hdr_    map[string]interface{}
+	pins    []string
+	workers int
+	poll    time.Duration
+}
+
+// NewBulkPublishService creates a new instance of BulkPublishService.
+func NewBulkPublishService(s *Service) *BulkPublishService {
+	rs := &BulkPublishService{
+		s:       s,
+		hdr_:    make(map[string]interface{}),
+		workers: DefaultBulkPublishWorkers,
+		poll:    DefaultWatchInterval,
+	}
+	return rs
+}
+
+// PINs of the catalogs. Duplicates are published only once.
+func (s *BulkPublishService) PINs(pins ...string) *BulkPublishService {
+	s.pins = append(s.pins, pins...)
+	return s
+}
+
+// Workers is the maximum number of catalogs published concurrently
+// (default 4).
+func (s *BulkPublishService) Workers(workers int) *BulkPublishService {
+	s.workers = workers
+	return s
+}
+
+// Poll is the interval in which the publish status of a catalog is
+// retrieved (default 5s).
+func (s *BulkPublishService) Poll(poll time.Duration) *BulkPublishService {
+	s.poll = poll
+	return s
+}
+
+// WithAuth overrides the user and password of the service for the
+// requests of this operation only.
+func (s *BulkPublishService) WithAuth(user, password string) *BulkPublishService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// Do executes the operation. Errors of single catalogs are reported in
+// the items of the response. Do only returns an error if ctx is done
+// before all catalogs have been published. In that case, it returns the
+// response together with the context error, and the catalogs that have
+// not been published report the context error in their items.
+func (s *BulkPublishService) Do(ctx context.Context) (*BulkPublishResponse, error) {
+	ret := new(BulkPublishResponse)
+	seen := make(map[string]bool)
+	for _, pin := range s.pins {
+		if !seen[pin] {
+			seen[pin] = true
+			ret.Items = append(ret.Items, &BulkPublishItem{PIN: pin})
+		}
+	}
+	err := meplatoapi.ForEach(ctx, len(ret.Items), s.workers, func(ctx context.Context, i int) {
+		item := ret.Items[i]
+		item.Status, item.Err = s.publish(ctx, item.PIN)
+	})
+	if err != nil {
+		for _, item := range ret.Items {
+			if item.Status == nil && item.Err == nil {
+				item.Err = err
+			}
+		}
+		return ret, err
+	}
+	return ret, nil
+}
+
+// publish publishes the catalog with the given PIN and waits until it is
+// done.
+func (s *BulkPublishService) publish(ctx context.Context, pin string) (*PublishStatusResponse, error) {
+	publish := s.s.Publish().PIN(pin)
+	for k, v := range s.hdr_ {
+		publish.hdr_[k] = v
+	}
+	if _, err := publish.Do(ctx); err != nil {
+		return nil, err
+	}
+
+	poll := s.poll
+	if poll <= 0 {
+		poll = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	var status *PublishStatusResponse
+	for {
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+		get := s.s.PublishStatus().PIN(pin)
+		for k, v := range s.hdr_ {
+			get.hdr_[k] = v
+		}
+		st, err := get.Do(ctx)
+		if err != nil {
+			return status, err
+		}
+		status = st
+		switch {
+		case status.Canceled:
+			return status, ErrPublishCanceled
+		case status.Done:
+			return status, nil
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package catalogs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestCatalogBulkPublish(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		published = make(map[string]int)
+		polled    = make(map[string]int)
+	)
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		mu.Lock()
+		defer mu.Unlock()
+		pin := strings.Split(strings.TrimPrefix(r.URL.Path, "/catalogs/"), "/")[0]
+		if pin == "unknown" {
+			return "catalogs.get.not_found"
+		}
+		if strings.HasSuffix(r.URL.Path, "/publish") {
+			published[pin]++
+			return "catalogs.publish.success"
+		}
+		polled[pin]++
+		switch {
+		case pin == "canceled":
+			return "catalogs.publish.canceled"
+		case polled[pin] < 3:
+			return "catalogs.publish.busy"
+		}
+		return "catalogs.publish.done"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.BulkPublish().
+		PINs("AD8CCDD5F9", "unknown", "5094310527", "canceled", "AD8CCDD5F9").
+		Workers(2).
+		Poll(time.Millisecond).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPINs := []string{"AD8CCDD5F9", "unknown", "5094310527", "canceled"}
+	if len(res.Items) != len(wantPINs) {
+		t.Fatalf("expected %d items; got: %d", len(wantPINs), len(res.Items))
+	}
+	for i, pin := range wantPINs {
+		if res.Items[i].PIN != pin {
+			t.Errorf("#%d: expected PIN %q; got: %q", i, pin, res.Items[i].PIN)
+		}
+	}
+	for _, item := range []*catalogs.BulkPublishItem{res.Items[0], res.Items[2]} {
+		if item.Err != nil {
+			t.Errorf("%s: expected no error; got: %v", item.PIN, item.Err)
+		}
+		if item.Status == nil || !item.Status.Done {
+			t.Errorf("%s: expected status to be done; got: %+v", item.PIN, item.Status)
+		}
+		if published[item.PIN] != 1 {
+			t.Errorf("%s: expected to be published once; got: %d", item.PIN, published[item.PIN])
+		}
+		if polled[item.PIN] != 3 {
+			t.Errorf("%s: expected status to be polled %d times; got: %d", item.PIN, 3, polled[item.PIN])
+		}
+	}
+	if res.Items[1].Err == nil || res.Items[1].Status != nil {
+		t.Errorf("expected error and no status for unknown catalog; got: %v, %+v", res.Items[1].Err, res.Items[1].Status)
+	}
+	if !errors.Is(res.Items[3].Err, catalogs.ErrPublishCanceled) {
+		t.Errorf("expected ErrPublishCanceled; got: %v", res.Items[3].Err)
+	}
+	if failed := res.Failed(); len(failed) != 2 {
+		t.Errorf("expected %d failed items; got: %d", 2, len(failed))
+	}
+}
+
+func TestCatalogBulkPublishCanceled(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if strings.HasSuffix(r.URL.Path, "/publish") {
+			return "catalogs.publish.success"
+		}
+		return "catalogs.publish.busy"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	res, err := service.BulkPublish().PINs("AD8CCDD5F9", "DEADBEEF").Workers(1).Poll(time.Millisecond).Do(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v; got: %v", context.DeadlineExceeded, err)
+	}
+	if res == nil {
+		t.Fatal("expected partial response; got: nil")
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected %d items; got: %d", 2, len(res.Items))
+	}
+	if item := res.Items[0]; item.Status == nil || !item.Status.Busy {
+		t.Errorf("expected last status of %s to be busy; got: %+v", item.PIN, item.Status)
+	}
+	if failed := res.Failed(); len(failed) != 2 {
+		t.Errorf("expected %d failed items; got: %d", 2, len(failed))
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
 	"strings"
@@ -60,7 +61,46 @@ type Service struct {
 	BaseURL  string
 	User     string
 	Password string
-}
+	// MaxRetries is the number of times a request is retried on network
+	// errors and transient server errors like 503 (default 0).
+	MaxRetries int
+	// Debug logs every request and response, including the indented JSON
+	// bodies, to Logger. The Authorization header is redacted.
+	Debug bool
+	// Logger is used in debug mode. If nil, the standard logger is used.
+	Logger Logger
+	// MaxResponseBytes limits the size of response bodies. Reading a larger
+	// body fails with an error that matches store2.ErrResponseTooLarge
+	// (default 0, i.e. unlimited).
+	MaxResponseBytes int64
+	// Trace, if set, creates a ClientTrace for every request, e.g. to
+	// measure DNS, connect, TLS, and time-to-first-byte timings. See
+	// store2.TimingTrace for a ready-made implementation.
+	Trace func(req *http.Request) *httptrace.ClientTrace
+	// ContextHeaders maps context keys to the names of HTTP headers. If
+	// the context of a request has a value for a key, e.g. a tenant or
+	// correlation ID stored by middleware, it is sent in the mapped header
+	// without passing it to every operation. Values are formatted with
+	// fmt.Sprint. Headers set by an operation take precedence.
+	ContextHeaders map[interface{}]string
+	// ObserveAttempts, if set, is called after every request with the
+	// number of attempts it took, i.e. 1 if it was not retried, e.g. to
+	// record retries in your metrics and alert when the backend is flaky.
+	ObserveAttempts func(req *http.Request, attempts int)
+
+	limiter    meplatoapi.Limiter
+	middleware []Middleware
+}
+
+// Logger is used to log requests and responses in debug mode. It is
+// implemented by *log.Logger.
+type Logger = meplatoapi.Logger
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc = meplatoapi.RoundTripFunc
+
+// Middleware wraps the RoundTripFunc of a service. See Service.Use.
+type Middleware = meplatoapi.Middleware
 
 func New(client *http.Client) (*Service, error) {
 	if client == nil {
@@ -69,6 +109,108 @@ func New(client *http.Client) (*Service, error) {
 	return &Service{client: client, BaseURL: baseURL}, nil
 }
 
+// NewFromEnv creates a new Service with a default HTTP client. The base
+// URL, user, and password are read from the STORE2_URL, STORE2_USER, and
+// STORE2_PASSWORD environment variables. If STORE2_URL is not set, the
+// default base URL is used. Debug logging is enabled if STORE2_DEBUG is
+// set to true.
+func NewFromEnv() (*Service, error) {
+	s, err := New(meplatoapi.NewDefaultClient())
+	if err != nil {
+		return nil, err
+	}
+	baseURL, user, password := meplatoapi.FromEnv()
+	if baseURL != "" {
+		s.BaseURL = baseURL
+	}
+	s.User = user
+	s.Password = password
+	s.Debug = meplatoapi.DebugFromEnv()
+	return s, nil
+}
+
+// SetMaxConcurrency limits the number of concurrent requests across all
+// operations of the service to n. Requests beyond the limit block until a
+// request finishes or their context is done. If n is less than or equal
+// to zero, the number of requests is not limited (default).
+func (s *Service) SetMaxConcurrency(n int) {
+	s.limiter.SetMax(n)
+}
+
+// WithProxy sends all requests of the service through the given proxy,
+// e.g. http://proxy.example.com:3128, so that services in one process can
+// use different proxies. It replaces the proxy from the HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY environment variables for this service; if
+// proxy is nil, requests are sent directly. The HTTP client of the
+// service is copied, so other users of the client are not affected. It
+// returns an error if the proxy URL is invalid or the client has a custom
+// transport that is not an *http.Transport.
+func (s *Service) WithProxy(proxy *url.URL) error {
+	client, err := meplatoapi.WithProxy(s.client, proxy)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+// InFlight returns the number of requests of the service that are
+// currently in flight.
+func (s *Service) InFlight() int {
+	return s.limiter.InFlight()
+}
+
+// Use adds middleware that wraps every request of the service, e.g. to
+// log, measure, cache, or rewrite requests and responses. Middleware runs
+// in the order it was added, i.e. the first one sees the request first
+// and the response last. It sees the request once, however often it is
+// retried, and the response body is already limited to MaxResponseBytes.
+// Use must not be called concurrently with requests.
+func (s *Service) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// do adds the headers of ContextHeaders to req, attaches the trace
+// created by Trace, and sends it through the middleware added by Use.
+func (s *Service) do(req *http.Request) (*http.Response, error) {
+	meplatoapi.SetContextHeaders(req, s.ContextHeaders)
+	req = meplatoapi.WithTrace(req, s.Trace)
+	return meplatoapi.Chain(s.send, s.middleware)(req)
+}
+
+// send sends req, retrying it according to MaxRetries and respecting the
+// limit set by SetMaxConcurrency. It reports the number of attempts to
+// ObserveAttempts, limits the response body to MaxResponseBytes, and, in
+// debug mode, logs the request and the response.
+func (s *Service) send(req *http.Request) (*http.Response, error) {
+	if s.Debug {
+		if err := meplatoapi.LogRequest(s.Logger, req); err != nil {
+			return nil, err
+		}
+	}
+	var attempts int
+	if s.ObserveAttempts != nil {
+		req = meplatoapi.CountAttempts(req, &attempts)
+	}
+	res, err := s.limiter.Send(s.client, req, s.MaxRetries)
+	if s.ObserveAttempts != nil {
+		s.ObserveAttempts(req, attempts)
+	}
+	if err == nil {
+		if err := meplatoapi.LimitBody(res, s.MaxResponseBytes); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	if err == nil && s.Debug {
+		if err := meplatoapi.LogResponse(s.Logger, res); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	return res, err
+}
+
 func (s *Service) Create() *CreateService {
 	return NewCreateService(s)
 }
@@ -232,6 +374,9 @@ type CreateCatalog struct {
 	// Currency is the ISO-4217 currency code that is used for all products in
 	// the catalog (e.g. EUR or USD).
 	Currency string `json:"currency,omitempty"`
+	// CustFields is an array of generic name/value pairs for
+	// customer-specific attributes.
+	CustFields []*CustField `json:"custFields,omitempty"`
 	// Description of the catalog.
 	Description string `json:"description,omitempty"`
 	// Language is the IETF language tag of the language of all products in
@@ -341,6 +486,11 @@ type PublishResponse struct {
 	// StatusLink returns the URL that returns the current status of the
 	// request.
 	StatusLink string `json:"statusLink,omitempty"`
+	// JobID is the ID of the background job that processes the request,
+	// if any. Use it with the jobs package, e.g. jobs.Service.WaitForState.
+	// It is taken from the jobId of the response or from a Location header
+	// that refers to a job.
+	JobID string `json:"jobId,omitempty"`
 }
 
 // PublishStatusResponse returns current information about the status of a
@@ -374,6 +524,11 @@ type PublishStatusResponse struct {
 type PurgeResponse struct {
 	// Kind is store#catalogPurge for this kind of response.
 	Kind string `json:"kind,omitempty"`
+	// JobID is the ID of the background job that processes the request,
+	// if any. Use it with the jobs package, e.g. jobs.Service.WaitForState.
+	// It is taken from the jobId of the response or from a Location header
+	// that refers to a job.
+	JobID string `json:"jobId,omitempty"`
 }
 
 // SearchResponse is a partial listing of catalogs.
@@ -383,6 +538,7 @@ type SearchResponse struct {
 	// Kind is store#catalogs for this kind of response.
 	Kind string `json:"kind,omitempty"`
 	// NextLink returns the URL to the next slice of catalogs (if any).
+	// If the response body has none, it is taken from the Link header.
 	NextLink string `json:"nextLink,omitempty"`
 	// PreviousLink returns the URL of the previous slice of catalogs (if
 	// any).
@@ -413,6 +569,13 @@ func (s *CreateService) Catalog(catalog *CreateCatalog) *CreateService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *CreateService) WithAuth(user, password string) *CreateService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *CreateService) Do(ctx context.Context) (*Catalog, error) {
 	var body io.Reader
@@ -429,7 +592,7 @@ func (s *CreateService) Do(ctx context.Context) (*Catalog, error) {
 		}
 		path += "?" + query.Encode()
 	}
-	req, err := http.NewRequest("POST", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("POST", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -441,7 +604,10 @@ func (s *CreateService) Do(ctx context.Context) (*Catalog, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -476,16 +642,26 @@ func (s *GetService) PIN(pin string) *GetService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *GetService) WithAuth(user, password string) *GetService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *GetService) Do(ctx context.Context) (*Catalog, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["pin"] = s.pin
+	if err := meplatoapi.RequireParams(params, "pin"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -497,7 +673,10 @@ func (s *GetService) Do(ctx context.Context) (*Catalog, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -532,16 +711,26 @@ func (s *PublishService) PIN(pin string) *PublishService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *PublishService) WithAuth(user, password string) *PublishService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *PublishService) Do(ctx context.Context) (*PublishResponse, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["pin"] = s.pin
+	if err := meplatoapi.RequireParams(params, "pin"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/publish", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("POST", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -553,7 +742,10 @@ func (s *PublishService) Do(ctx context.Context) (*PublishResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -565,6 +757,7 @@ func (s *PublishService) Do(ctx context.Context) (*PublishResponse, error) {
 	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
 		return nil, err
 	}
+	ret.JobID = meplatoapi.JobID(ret.JobID, res)
 	return ret, nil
 }
 
@@ -588,16 +781,26 @@ func (s *PublishStatusService) PIN(pin string) *PublishStatusService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *PublishStatusService) WithAuth(user, password string) *PublishStatusService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *PublishStatusService) Do(ctx context.Context) (*PublishStatusResponse, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["pin"] = s.pin
+	if err := meplatoapi.RequireParams(params, "pin"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/publish/status", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -609,7 +812,10 @@ func (s *PublishStatusService) Do(ctx context.Context) (*PublishStatusResponse,
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -652,17 +858,27 @@ func (s *PurgeService) PIN(pin string) *PurgeService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *PurgeService) WithAuth(user, password string) *PurgeService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *PurgeService) Do(ctx context.Context) (*PurgeResponse, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["area"] = s.area
 	params["pin"] = s.pin
+	if err := meplatoapi.RequireParams(params, "pin", "area"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("DELETE", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("DELETE", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -674,7 +890,10 @@ func (s *PurgeService) Do(ctx context.Context) (*PurgeResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -686,6 +905,7 @@ func (s *PurgeService) Do(ctx context.Context) (*PurgeResponse, error) {
 	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
 		return nil, err
 	}
+	ret.JobID = meplatoapi.JobID(ret.JobID, res)
 	return ret, nil
 }
 
@@ -714,7 +934,8 @@ func (s *SearchService) Skip(skip int64) *SearchService {
 	return s
 }
 
-// Sort order, e.g. name or id or -created (default: score).
+// Sort order, e.g. name or id or -created (default: score). Unknown sort
+// keys are rejected by Do; see ValidateSort and the SortBy constants.
 func (s *SearchService) Sort(sort string) *SearchService {
 	s.opt_["sort"] = sort
 	return s
@@ -726,9 +947,21 @@ func (s *SearchService) Take(take int64) *SearchService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *SearchService) WithAuth(user, password string) *SearchService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	var body io.Reader
+	if v, ok := s.opt_["sort"]; ok {
+		if err := ValidateSort(fmt.Sprint(v)); err != nil {
+			return nil, err
+		}
+	}
 	params := make(map[string]interface{})
 	if v, ok := s.opt_["q"]; ok {
 		params["q"] = v
@@ -746,7 +979,7 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -758,7 +991,10 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -770,5 +1006,12 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
 		return nil, err
 	}
+	next, prev := meplatoapi.PageLinks(res)
+	if ret.NextLink == "" {
+		ret.NextLink = next
+	}
+	if ret.PreviousLink == "" {
+		ret.PreviousLink = prev
+	}
 	return ret, nil
 }
@@ -1,49 +1,34 @@
 package catalogs_test
 
 import (
-	"bufio"
 	"context"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"path"
-	"strings"
 	"testing"
 
 	"github.com/meplato/store2-go-client/v2/catalogs"
+	"github.com/meplato/store2-go-client/v2/storetest"
 )
 
 func getService(responseFile string) (*catalogs.Service, *httptest.Server, error) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slurp, err := ioutil.ReadFile(path.Join("testdata", responseFile))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(string(slurp))), r)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer res.Body.Close()
-		bs, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(res.StatusCode)
-		fmt.Fprint(w, string(bs))
-	}))
+	return getServiceWithRoutes(func(r *http.Request) string {
+		return responseFile
+	})
+}
+
+// getServiceWithRoutes returns a service whose test server replies with
+// the response file returned by route for each incoming request.
+func getServiceWithRoutes(route func(r *http.Request) string) (*catalogs.Service, *httptest.Server, error) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		return path.Join("testdata", route(r))
+	})
 
-	service, err := catalogs.New(http.DefaultClient)
+	service, err := catalogs.NewFromEnv()
 	if err != nil {
 		return service, nil, err
 	}
 	service.BaseURL = ts.URL
-	service.User = os.Getenv("STORE2_USER")
-	service.Password = os.Getenv("STORE2_PASSWORD")
 	return service, ts, nil
 }
 
@@ -66,6 +51,33 @@ func TestCatalogSearch(t *testing.T) {
 	}
 }
 
+func TestCatalogSearchSort(t *testing.T) {
+	service, ts, err := getService("catalogs.search.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	res, err := service.Search().Sort("-created,id").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil {
+		t.Fatal("expected response; got: nil")
+	}
+
+	res, err = service.Search().Sort(catalogs.SortByNameAsc + ",spn").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error for invalid sort key; got: nil")
+	}
+	if res != nil {
+		t.Fatalf("expected no response; got: %v", res)
+	}
+}
+
 func TestCatalogCreate(t *testing.T) {
 	service, ts, err := getService("catalogs.create.success")
 	if err != nil {
@@ -216,3 +228,87 @@ func TestCatalogPurge(t *testing.T) {
 		t.Errorf("expected %q; got: %q", "store#catalogPurge", c.Kind)
 	}
 }
+
+func TestCatalogSearchIterator(t *testing.T) {
+	service, ts, err := getService("catalogs.search.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	it := service.Search().Iterator()
+	var n int
+	for {
+		items, ok, err := it.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		n += len(items)
+	}
+	if n != 2 {
+		t.Fatalf("expected %d catalogs; got: %d", 2, n)
+	}
+}
+
+func TestCatalogSearchStream(t *testing.T) {
+	service, ts, err := getService("catalogs.search.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	catalogs, errc := service.Search().Stream(context.Background())
+	var n int
+	for c := range catalogs {
+		if c == nil {
+			t.Fatal("expected catalog; got: nil")
+		}
+		n++
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected %d catalogs; got: %d", 2, n)
+	}
+}
+
+func TestCatalogSearchStreamCanceled(t *testing.T) {
+	service, ts, err := getService("catalogs.search.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	catalogs, errc := service.Search().Stream(ctx)
+	if _, ok := <-catalogs; !ok {
+		t.Fatal("expected a catalog before canceling")
+	}
+	cancel()
+	// The goroutine is blocked sending the second catalog, so it must
+	// notice the cancellation without anyone receiving
+	if err := <-errc; err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+	if _, ok := <-catalogs; ok {
+		t.Fatal("expected catalogs channel to be closed")
+	}
+
+	// A failing request is surfaced on the error channel
+	service, ts2, err := getService("catalogs.search.unauthorized")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts2.Close()
+	catalogs, errc = service.Search().Stream(context.Background())
+	for range catalogs {
+		t.Fatal("expected no catalogs")
+	}
+	if err := <-errc; err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// DefaultCountsWorkers is the number of concurrent requests used by
+// CountsService unless configured otherwise.
+const DefaultCountsWorkers = 4
+
+func (s *Service) Counts() *CountsService {
+	return NewCountsService(s)
+}
+
+// Counts is the number of products in the work and live area of a
+// catalog.
+type Counts struct {
+	// Work is the number of products in the work area.
+	Work int64
+	// Live is the number of products in the live area.
+	Live int64
+}
+
+// CountsResponse is the outcome of getting the product counts of several
+// catalogs.
+type CountsResponse struct {
+	// Items contains the product counts, indexed by PIN.
+	Items map[string]*Counts
+}
+
+// CountsService returns the number of products in the work and live area
+// of one or more catalogs. As the API has no dedicated endpoint for
+// product counts, it gets the catalogs with bounded concurrency and
+// returns only the counts.
+type CountsService struct {
+	s       *Service
+	hdr_    map[string]interface{}
+	pins    []string
+	workers int
+}
+
+// NewCountsService creates a new instance of CountsService.
+func NewCountsService(s *Service) *CountsService {
+	rs := &CountsService{s: s, hdr_: make(map[string]interface{}), workers: DefaultCountsWorkers}
+	return rs
+}
+
+// PINs of the catalogs. Duplicates are fetched only once.
+func (s *CountsService) PINs(pins ...string) *CountsService {
+	s.pins = append(s.pins, pins...)
+	return s
+}
+
+// Workers is the maximum number of concurrent requests (default 4).
+func (s *CountsService) Workers(workers int) *CountsService {
+	s.workers = workers
+	return s
+}
+
+// WithAuth overrides the user and password of the service for the
+// requests of this operation only.
+func (s *CountsService) WithAuth(user, password string) *CountsService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// Do executes the operation. It returns the first error that occurs, or
+// the context error if ctx is canceled.
+func (s *CountsService) Do(ctx context.Context) (*CountsResponse, error) {
+	var pins []string
+	seen := make(map[string]bool)
+	for _, pin := range s.pins {
+		if !seen[pin] {
+			seen[pin] = true
+			pins = append(pins, pin)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	items := make([]*Catalog, len(pins))
+	errs := make([]error, len(pins))
+	err := meplatoapi.ForEach(ctx, len(pins), s.workers, func(ctx context.Context, i int) {
+		get := s.s.Get().PIN(pins[i])
+		for k, v := range s.hdr_ {
+			get.hdr_[k] = v
+		}
+		items[i], errs[i] = get.Do(ctx)
+		if errs[i] != nil {
+			cancel()
+		}
+	})
+
+	ret := &CountsResponse{Items: make(map[string]*Counts)}
+	for i, pin := range pins {
+		if errs[i] != nil && !errors.Is(errs[i], context.Canceled) {
+			return nil, errs[i]
+		}
+		if items[i] == nil {
+			continue
+		}
+		counts := new(Counts)
+		if items[i].NumProductsWork != nil {
+			counts.Work = *items[i].NumProductsWork
+		}
+		if items[i].NumProductsLive != nil {
+			counts.Live = *items[i].NumProductsLive
+		}
+		ret.Items[pin] = counts
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
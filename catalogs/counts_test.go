@@ -0,0 +1,51 @@
+package catalogs_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestCatalogCounts(t *testing.T) {
+	service, ts, err := getService("catalogs.get.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Counts().PINs("AD8CCDD5F9", "5094310527", "AD8CCDD5F9").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected %d items; got: %d", 2, len(res.Items))
+	}
+	counts := res.Items["AD8CCDD5F9"]
+	if counts == nil {
+		t.Fatal("expected counts for AD8CCDD5F9; got: nil")
+	}
+	if want := (catalogs.Counts{Work: 2, Live: 0}); *counts != want {
+		t.Fatalf("expected %+v; got: %+v", want, *counts)
+	}
+}
+
+func TestCatalogCountsNotFound(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if strings.HasSuffix(r.URL.Path, "/unknown") {
+			return "catalogs.get.not_found"
+		}
+		return "catalogs.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	_, err = service.Counts().PINs("AD8CCDD5F9", "unknown").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+}
@@ -0,0 +1,49 @@
+package catalogs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestCatalogCreateCustFields(t *testing.T) {
+	var sent catalogs.CreateCatalog
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+		}
+		return "catalogs.create.custfields"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	custFields := []*catalogs.CustField{
+		{Name: "CostCenter", Value: "4711"},
+		{Name: "TaxRate", Value: "19%"},
+	}
+	create := &catalogs.CreateCatalog{
+		MerchantID:  1,
+		Name:        "test2",
+		ProjectMpcc: "meplato",
+		Country:     "DE",
+		Currency:    "EUR",
+		Language:    "de",
+		CustFields:  custFields,
+	}
+	cat, err := service.Create().Catalog(create).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sent.CustFields, custFields) {
+		t.Errorf("expected custom fields %v to be sent; got: %v", custFields, sent.CustFields)
+	}
+	if !reflect.DeepEqual(cat.CustFields, custFields) {
+		t.Errorf("expected custom fields %v to be returned; got: %v", custFields, cat.CustFields)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"context"
+	"time"
+)
+
+// ExpiresAt returns the time the catalog expires, i.e. the end of the day
+// given by ValidUntil, in UTC. It returns false if the catalog has no
+// ValidUntil date or the date is invalid.
+func ExpiresAt(c *Catalog) (time.Time, bool) {
+	if c == nil || c.ValidUntil == nil || *c.ValidUntil == "" {
+		return time.Time{}, false
+	}
+	until, err := time.Parse("2006-01-02", *c.ValidUntil)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until.AddDate(0, 0, 1), true
+}
+
+// ExpiresWithin reports whether the catalog expires within d from now,
+// e.g. to renew it before it lapses. It is computed from ValidUntil and
+// returns false for catalogs without a ValidUntil date and for catalogs
+// that have already expired.
+func ExpiresWithin(c *Catalog, d time.Duration) bool {
+	return expiresWithin(c, d, time.Now())
+}
+
+func expiresWithin(c *Catalog, d time.Duration, now time.Time) bool {
+	if c == nil || c.Expired {
+		return false
+	}
+	at, ok := ExpiresAt(c)
+	if !ok {
+		return false
+	}
+	return at.After(now) && !at.After(now.Add(d))
+}
+
+// ExpiringWithin pages through the search result and returns the
+// catalogs that expire within d from now, in the order of the search
+// result. See ExpiresWithin for details.
+func (s *SearchService) ExpiringWithin(ctx context.Context, d time.Duration) ([]*Catalog, error) {
+	now := time.Now()
+	var expiring []*Catalog
+	it := s.Iterator()
+	for {
+		items, ok, err := it.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return expiring, nil
+		}
+		for _, c := range items {
+			if expiresWithin(c, d, now) {
+				expiring = append(expiring, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package catalogs_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+// validUntil returns the ValidUntil date days from today.
+func validUntil(days int) string {
+	return time.Now().UTC().AddDate(0, 0, days).Format("2006-01-02")
+}
+
+func TestExpiresWithin(t *testing.T) {
+	date := func(s string) *string { return &s }
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		Catalog *catalogs.Catalog
+		Want    bool
+	}{
+		{&catalogs.Catalog{ValidUntil: date(validUntil(0))}, true},
+		{&catalogs.Catalog{ValidUntil: date(validUntil(3))}, true},
+		{&catalogs.Catalog{ValidUntil: date(validUntil(5))}, true},
+		{&catalogs.Catalog{ValidUntil: date(validUntil(7))}, false},
+		{&catalogs.Catalog{ValidUntil: date(validUntil(30))}, false},
+		{&catalogs.Catalog{ValidUntil: date(validUntil(-1))}, false},
+		{&catalogs.Catalog{ValidUntil: date(validUntil(3)), Expired: true}, false},
+		{&catalogs.Catalog{ValidUntil: date("31.12.2015")}, false},
+		{&catalogs.Catalog{ValidUntil: date("")}, false},
+		{&catalogs.Catalog{}, false},
+		{nil, false},
+	}
+	for i, tt := range tests {
+		if got := catalogs.ExpiresWithin(tt.Catalog, week); got != tt.Want {
+			t.Errorf("%d. expected %v; got: %v", i, tt.Want, got)
+		}
+	}
+}
+
+func TestExpiresAt(t *testing.T) {
+	until := "2015-12-31"
+	at, ok := catalogs.ExpiresAt(&catalogs.Catalog{ValidUntil: &until})
+	if !ok {
+		t.Fatal("expected expiry date")
+	}
+	if want := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC); !at.Equal(want) {
+		t.Errorf("expected %v; got: %v", want, at)
+	}
+}
+
+func TestSearchExpiringWithin(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("skip") == "0" || r.URL.Query().Get("skip") == "" {
+			fmt.Fprintf(w, `{"kind":"store#catalogs","totalItems":4,"items":[{"pin":"A","validUntil":%q},{"pin":"B","validUntil":%q}]}`, validUntil(2), validUntil(60))
+		} else {
+			fmt.Fprintf(w, `{"kind":"store#catalogs","totalItems":4,"items":[{"pin":"C"},{"pin":"D","validUntil":%q}]}`, validUntil(10))
+		}
+	}))
+	defer ts.Close()
+	service, err := catalogs.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	expiring, err := service.Search().Take(2).ExpiringWithin(context.Background(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pins []string
+	for _, c := range expiring {
+		pins = append(pins, c.PIN)
+	}
+	if fmt.Sprint(pins) != "[A D]" {
+		t.Errorf("expected catalogs [A D]; got: %v", pins)
+	}
+}
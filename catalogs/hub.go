@@ -0,0 +1,45 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ProductHubURL returns a Meplato Hub URL for the product with the given
+// SPN in the catalog, e.g.
+// https://hub.meplato.de/forward/12345/shop/products/ABC%2F123 for the SPN
+// "ABC/123". It appends /products/ and the SPN, escaped as a single path
+// segment, to the path of the HubURL of the catalog and keeps its query.
+// The catalog must have been returned by the API, e.g. by Get.
+//
+// The API does not document product URLs for Meplato Hub, so this route
+// is an assumption that has not been verified. Check the links in your
+// environment before you rely on them.
+//
+// ProductHubURL returns an empty string if the catalog has no valid
+// HubURL or spn is empty.
+func ProductHubURL(catalog *Catalog, spn string) string {
+	if catalog == nil || catalog.HubURL == "" || spn == "" {
+		return ""
+	}
+	u, err := url.Parse(catalog.HubURL)
+	if err != nil {
+		return ""
+	}
+	rawPath := strings.TrimRight(u.EscapedPath(), "/") + "/products/" + url.PathEscape(spn)
+	u.Path = strings.TrimRight(u.Path, "/") + "/products/" + spn
+	u.RawPath = rawPath
+	return u.String()
+}
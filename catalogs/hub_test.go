@@ -0,0 +1,32 @@
+package catalogs_test
+
+import (
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestProductHubURL(t *testing.T) {
+	catalog := &catalogs.Catalog{HubURL: "https://hub.meplato.de/forward/12345/shop"}
+	tests := []struct {
+		Catalog *catalogs.Catalog
+		Spn     string
+		Want    string
+	}{
+		{catalog, "1000", "https://hub.meplato.de/forward/12345/shop/products/1000"},
+		{catalog, "ABC/123", "https://hub.meplato.de/forward/12345/shop/products/ABC%2F123"},
+		{catalog, "A B?#", "https://hub.meplato.de/forward/12345/shop/products/A%20B%3F%23"},
+		{&catalogs.Catalog{HubURL: "https://hub.meplato.de/forward/12345/shop/"}, "1000", "https://hub.meplato.de/forward/12345/shop/products/1000"},
+		{&catalogs.Catalog{HubURL: "https://hub.meplato.de/forward/12345/shop?lang=de"}, "ABC/123", "https://hub.meplato.de/forward/12345/shop/products/ABC%2F123?lang=de"},
+		{&catalogs.Catalog{HubURL: "https://hub.meplato.de/forward/a%2Fb/shop/"}, "1000", "https://hub.meplato.de/forward/a%2Fb/shop/products/1000"},
+		{&catalogs.Catalog{HubURL: "://invalid"}, "1000", ""},
+		{catalog, "", ""},
+		{&catalogs.Catalog{}, "1000", ""},
+		{nil, "1000", ""},
+	}
+	for i, tt := range tests {
+		if got := catalogs.ProductHubURL(tt.Catalog, tt.Spn); got != tt.Want {
+			t.Errorf("%d. expected %q; got: %q", i, tt.Want, got)
+		}
+	}
+}
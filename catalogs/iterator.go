@@ -0,0 +1,74 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"context"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// Iterator iterates over the pages of catalogs. Call Next until it reports
+// that there are no more pages.
+type Iterator = meplatoapi.Paginator[*Catalog]
+
+// Iterator returns an Iterator over the pages of the search result,
+// starting at Skip. The page size is set by Take.
+func (s *SearchService) Iterator() *Iterator {
+	skip, _ := s.opt_["skip"].(int64)
+	var total int64
+	return meplatoapi.NewPaginator(meplatoapi.SkipTake(skip, func(ctx context.Context, skip int64) ([]*Catalog, int64, error) {
+		res, err := s.Skip(skip).Do(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		total = res.TotalItems
+		return res.Items, res.TotalItems, nil
+	})).WithTotal(func() int64 { return total })
+}
+
+// Stream pages through the search result like Iterator in a separate
+// goroutine and sends the catalogs on the returned channel as they arrive.
+// Both channels are closed when there are no more catalogs, an error
+// occurs, or ctx is done. The error, including the error of ctx, is sent
+// on the error channel, which is buffered and never receives more than
+// one error. Callers should drain the catalog channel until it is closed
+// and check the error channel afterwards.
+func (s *SearchService) Stream(ctx context.Context) (<-chan *Catalog, <-chan error) {
+	out := make(chan *Catalog)
+	errc := make(chan error, 1)
+	it := s.Iterator()
+	go func() {
+		defer close(errc)
+		defer close(out)
+		for {
+			items, ok, err := it.Next(ctx)
+			if err != nil {
+				errc <- err
+				return
+			}
+			if !ok {
+				return
+			}
+			for _, c := range items {
+				select {
+				case out <- c:
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+			}
+		}
+	}()
+	return out, errc
+}
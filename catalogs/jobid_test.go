@@ -0,0 +1,35 @@
+package catalogs_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCatalogPublishJobID(t *testing.T) {
+	service, ts, err := getService("catalogs.publish.job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Publish().PIN("AD8CCDD5F9").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "58097dc3-b279-49b5-a5da-23eb1c77d840"; res.JobID != want {
+		t.Fatalf("expected job ID %q; got: %q", want, res.JobID)
+	}
+
+	service, ts2, err := getService("catalogs.publish.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts2.Close()
+	res, err = service.Publish().PIN("AD8CCDD5F9").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.JobID != "" {
+		t.Fatalf("expected no job ID; got: %q", res.JobID)
+	}
+}
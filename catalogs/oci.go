@@ -0,0 +1,151 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// OciParams are the parameters of an OCI punchout session, i.e. a round
+// trip from the purchasing system of the buyer to the shop of the
+// supplier and back.
+type OciParams struct {
+	// HookURL is the URL of the purchasing system that the shopping cart
+	// is sent back to (required).
+	HookURL string
+	// Username is the user to log into the shop with.
+	Username string
+	// Password is the password of Username.
+	Password string
+	// Function is the OCI transaction to start, e.g. DETAIL or VALIDATE.
+	// If empty, a regular shopping session is started.
+	Function string
+	// ProductID is the product to show, e.g. for DETAIL or VALIDATE.
+	ProductID string
+	// Quantity is the quantity of ProductID, e.g. for VALIDATE.
+	Quantity string
+	// SearchString is the query for BACKGROUNDSEARCH.
+	SearchString string
+	// OkCode is passed as ~OkCode (default ADDI).
+	OkCode string
+	// Target is the frame the cart is returned to, passed as ~TARGET
+	// (default _top).
+	Target string
+	// Caller is passed as ~CALLER (default CTLG).
+	Caller string
+	// Extra are additional parameters to pass to the shop.
+	Extra url.Values
+}
+
+// ociFunctions maps the OCI transactions to whether a catalog supports
+// them.
+var ociFunctions = map[string]func(c *Catalog) bool{
+	"BACKGROUNDSEARCH": func(c *Catalog) bool { return c.SupportsOciBackgroundsearch },
+	"DETAIL":           func(c *Catalog) bool { return c.SupportsOciDetail },
+	"DETAILADD":        func(c *Catalog) bool { return c.SupportsOciDetailadd },
+	"DOWNLOADJSON":     func(c *Catalog) bool { return c.SupportsOciDownloadjson },
+	"QUANTITYCHECK":    func(c *Catalog) bool { return c.SupportsOciQuantitycheck },
+	"SOURCING":         func(c *Catalog) bool { return c.SupportsOciSourcing },
+	"VALIDATE":         func(c *Catalog) bool { return c.SupportsOciValidate },
+}
+
+// ValidateOciURL checks that rawurl can be used as an OCI punchout or hook
+// URL, i.e. that it is an absolute http or https URL with a host and
+// without a fragment.
+func ValidateOciURL(rawurl string) error {
+	if err := validateOciURL(rawurl); err != nil {
+		return fmt.Errorf("catalogs: invalid OCI URL: %v", err)
+	}
+	return nil
+}
+
+// validateOciURL implements ValidateOciURL.
+func validateOciURL(rawurl string) error {
+	if rawurl == "" {
+		return errors.New("URL is empty")
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q: scheme must be http or https", rawurl)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q: no host", rawurl)
+	}
+	if u.Fragment != "" {
+		return fmt.Errorf("%q: must not have a fragment", rawurl)
+	}
+	return nil
+}
+
+// OciPunchoutURL returns the URL to redirect the user to in order to start
+// an OCI punchout session in the catalog. It adds the standard OCI
+// parameters like HOOK_URL and USERNAME to the OciURL of the catalog,
+// replacing parameters of the same name; other parameters of the OciURL
+// are kept. It returns an error if the OciURL or the HookURL are invalid,
+// or if the catalog does not support the requested OCI function.
+func OciPunchoutURL(catalog *Catalog, params *OciParams) (string, error) {
+	if catalog == nil {
+		return "", errors.New("catalogs: no catalog")
+	}
+	if params == nil {
+		return "", errors.New("catalogs: no OCI parameters")
+	}
+	if err := ValidateOciURL(catalog.OciURL); err != nil {
+		return "", err
+	}
+	if err := validateOciURL(params.HookURL); err != nil {
+		return "", fmt.Errorf("catalogs: invalid HOOK_URL: %v", err)
+	}
+	function := strings.ToUpper(params.Function)
+	if function != "" {
+		supported, found := ociFunctions[function]
+		if !found {
+			return "", fmt.Errorf("catalogs: unknown OCI function %q", params.Function)
+		}
+		if !supported(catalog) {
+			return "", fmt.Errorf("catalogs: catalog %s does not support OCI function %s", catalog.PIN, function)
+		}
+	}
+
+	u, _ := url.Parse(catalog.OciURL)
+	q := u.Query()
+	for name, values := range params.Extra {
+		q[name] = values
+	}
+	set := func(name, value, def string) {
+		if value == "" {
+			value = def
+		}
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	set("HOOK_URL", params.HookURL, "")
+	set("USERNAME", params.Username, "")
+	set("PASSWORD", params.Password, "")
+	set("FUNCTION", function, "")
+	set("PRODUCTID", params.ProductID, "")
+	set("QUANTITY", params.Quantity, "")
+	set("SEARCHSTRING", params.SearchString, "")
+	set("~OkCode", params.OkCode, "ADDI")
+	set("~TARGET", params.Target, "_top")
+	set("~CALLER", params.Caller, "CTLG")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,108 @@
+package catalogs_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestValidateOciURL(t *testing.T) {
+	tests := []struct {
+		URL   string
+		Valid bool
+	}{
+		{"https://my-shop.com/oci?param1=a", true},
+		{"http://my-shop.com/oci", true},
+		{"", false},
+		{"my-shop.com/oci", false},
+		{"ftp://my-shop.com/oci", false},
+		{"https:///oci", false},
+		{"https://my-shop.com/oci#top", false},
+		{"https://my shop.com/%zz", false},
+	}
+	for i, tt := range tests {
+		err := catalogs.ValidateOciURL(tt.URL)
+		if tt.Valid && err != nil {
+			t.Errorf("%d. expected %q to be valid; got: %v", i, tt.URL, err)
+		}
+		if !tt.Valid && err == nil {
+			t.Errorf("%d. expected %q to be invalid; got: nil", i, tt.URL)
+		}
+	}
+}
+
+func TestOciPunchoutURL(t *testing.T) {
+	catalog := &catalogs.Catalog{
+		PIN:               "AD8CCDD5F9",
+		OciURL:            "https://my-shop.com/oci?param1=a&USERNAME=old",
+		SupportsOciDetail: true,
+	}
+	params := &catalogs.OciParams{
+		HookURL:   "https://erp.example.com/hook?sid=1&x=y",
+		Username:  "joe",
+		Password:  "secret",
+		Function:  "detail",
+		ProductID: "ABC/123",
+		Extra:     url.Values{"lang": {"de"}},
+	}
+	got, err := catalogs.OciPunchoutURL(catalog, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "my-shop.com" || u.Path != "/oci" {
+		t.Errorf("expected URL of the shop; got: %s", got)
+	}
+	want := map[string]string{
+		"param1":    "a",
+		"lang":      "de",
+		"HOOK_URL":  "https://erp.example.com/hook?sid=1&x=y",
+		"USERNAME":  "joe",
+		"PASSWORD":  "secret",
+		"FUNCTION":  "DETAIL",
+		"PRODUCTID": "ABC/123",
+		"~OkCode":   "ADDI",
+		"~TARGET":   "_top",
+		"~CALLER":   "CTLG",
+	}
+	q := u.Query()
+	for name, value := range want {
+		if got := q[name]; len(got) != 1 || got[0] != value {
+			t.Errorf("expected %s=%q; got: %q", name, value, got)
+		}
+	}
+	for _, name := range []string{"QUANTITY", "SEARCHSTRING"} {
+		if _, found := q[name]; found {
+			t.Errorf("expected no %s; got: %q", name, q.Get(name))
+		}
+	}
+}
+
+func TestOciPunchoutURLErrors(t *testing.T) {
+	catalog := &catalogs.Catalog{PIN: "AD8CCDD5F9", OciURL: "https://my-shop.com/oci"}
+	hook := "https://erp.example.com/hook"
+	tests := []struct {
+		Catalog *catalogs.Catalog
+		Params  *catalogs.OciParams
+		Want    string
+	}{
+		{nil, &catalogs.OciParams{HookURL: hook}, "no catalog"},
+		{catalog, nil, "no OCI parameters"},
+		{&catalogs.Catalog{}, &catalogs.OciParams{HookURL: hook}, "invalid OCI URL"},
+		{catalog, &catalogs.OciParams{}, "invalid HOOK_URL"},
+		{catalog, &catalogs.OciParams{HookURL: "/hook"}, "invalid HOOK_URL"},
+		{catalog, &catalogs.OciParams{HookURL: hook, Function: "CHECKOUT"}, "unknown OCI function"},
+		{catalog, &catalogs.OciParams{HookURL: hook, Function: "VALIDATE"}, "does not support"},
+	}
+	for i, tt := range tests {
+		_, err := catalogs.OciPunchoutURL(tt.Catalog, tt.Params)
+		if err == nil || !strings.Contains(err.Error(), tt.Want) {
+			t.Errorf("%d. expected error containing %q; got: %v", i, tt.Want, err)
+		}
+	}
+}
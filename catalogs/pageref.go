@@ -0,0 +1,41 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import "github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+
+// PageRef refers to a page of a search by skip and take. Use
+// SearchResponse.NextPage to get the reference to the next page, persist
+// it, and pass it to SearchService.Page to resume the search.
+type PageRef = meplatoapi.PageRef
+
+// NextPage returns the reference to the next page of the search, parsed
+// from NextLink. It returns nil if there is no next page.
+func (r *SearchResponse) NextPage() (*PageRef, error) {
+	return meplatoapi.ParsePageRef(r.NextLink)
+}
+
+// PreviousPage returns the reference to the previous page of the search,
+// parsed from PreviousLink. It returns nil if there is no previous page.
+func (r *SearchResponse) PreviousPage() (*PageRef, error) {
+	return meplatoapi.ParsePageRef(r.PreviousLink)
+}
+
+// Page sets Skip and Take from ref. A Take of 0 keeps the server default.
+func (s *SearchService) Page(ref PageRef) *SearchService {
+	s.Skip(ref.Skip)
+	if ref.Take > 0 {
+		s.Take(ref.Take)
+	}
+	return s
+}
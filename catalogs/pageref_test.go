@@ -0,0 +1,33 @@
+package catalogs_test
+
+import (
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestCatalogSearchPageRef(t *testing.T) {
+	res := &catalogs.SearchResponse{
+		NextLink:     "https://store.meplato.com/api/v2/catalogs?skip=40&take=20",
+		PreviousLink: "https://store.meplato.com/api/v2/catalogs?take=20",
+	}
+	next, err := res.NextPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (catalogs.PageRef{Skip: 40, Take: 20}); next == nil || *next != want {
+		t.Fatalf("expected next page %+v; got: %+v", want, next)
+	}
+	prev, err := res.PreviousPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (catalogs.PageRef{Skip: 0, Take: 20}); prev == nil || *prev != want {
+		t.Fatalf("expected previous page %+v; got: %+v", want, prev)
+	}
+
+	res.NextLink = ""
+	if next, err := res.NextPage(); err != nil || next != nil {
+		t.Fatalf("expected no next page; got: %+v, %v", next, err)
+	}
+}
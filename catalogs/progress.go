@@ -0,0 +1,49 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"fmt"
+)
+
+// Progress returns the progress of the publish request in the range 0..1.
+// It prefers Percent and falls back to CurrentStep and TotalSteps if the
+// server did not report a percentage. A finished request always reports 1.
+func (r *PublishStatusResponse) Progress() float64 {
+	var p float64
+	switch {
+	case r.Done:
+		return 1
+	case r.Percent > 0:
+		p = float64(r.Percent) / 100
+	case r.TotalSteps > 0:
+		p = float64(r.CurrentStep) / float64(r.TotalSteps)
+	}
+	if p < 0 {
+		return 0
+	}
+	if p > 1 {
+		return 1
+	}
+	return p
+}
+
+// String returns the progress of the publish request for display,
+// e.g. "Step 3 of 10 (30%)".
+func (r *PublishStatusResponse) String() string {
+	s := fmt.Sprintf("Step %d of %d (%.0f%%)", r.CurrentStep, r.TotalSteps, r.Progress()*100)
+	if r.Status != "" {
+		s += " " + r.Status
+	}
+	return s
+}
@@ -0,0 +1,29 @@
+package catalogs_test
+
+import (
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestPublishStatusProgress(t *testing.T) {
+	tests := []struct {
+		Status   catalogs.PublishStatusResponse
+		Progress float64
+		String   string
+	}{
+		{catalogs.PublishStatusResponse{}, 0, "Step 0 of 0 (0%)"},
+		{catalogs.PublishStatusResponse{Percent: 40, CurrentStep: 1, TotalSteps: 10}, 0.4, "Step 1 of 10 (40%)"},
+		{catalogs.PublishStatusResponse{CurrentStep: 3, TotalSteps: 4, Status: "Indexing"}, 0.75, "Step 3 of 4 (75%) Indexing"},
+		{catalogs.PublishStatusResponse{CurrentStep: 12, TotalSteps: 10}, 1, "Step 12 of 10 (100%)"},
+		{catalogs.PublishStatusResponse{Percent: 99, Done: true}, 1, "Step 0 of 0 (100%)"},
+	}
+	for i, test := range tests {
+		if got := test.Status.Progress(); got != test.Progress {
+			t.Errorf("#%d: expected progress %v; got: %v", i, test.Progress, got)
+		}
+		if got := test.Status.String(); got != test.String {
+			t.Errorf("#%d: expected %q; got: %q", i, test.String, got)
+		}
+	}
+}
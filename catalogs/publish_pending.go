@@ -0,0 +1,43 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"context"
+)
+
+// PublishPending reports whether the work area of the catalog has changes
+// that are not yet live, i.e. the catalog was imported after it was last
+// published, or it has never been published but contains products.
+// The catalog must have been retrieved with Get, as Search does not
+// return all the necessary fields.
+func (c *Catalog) PublishPending() bool {
+	if c.LastImported != nil && (c.LastPublished == nil || c.LastImported.After(*c.LastPublished)) {
+		return true
+	}
+	if c.LastPublished == nil && c.PublishedVersion == nil {
+		return c.NumProductsWork != nil && *c.NumProductsWork > 0
+	}
+	return false
+}
+
+// NeedsPublish gets the catalog with the given PIN and reports whether
+// its work area has changes that are not yet live. See
+// Catalog.PublishPending for details.
+func (s *Service) NeedsPublish(ctx context.Context, pin string) (bool, error) {
+	cat, err := s.Get().PIN(pin).Do(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cat.PublishPending(), nil
+}
@@ -0,0 +1,55 @@
+package catalogs_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestCatalogPublishPending(t *testing.T) {
+	earlier := time.Date(2015, 3, 26, 16, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	version := int64(3)
+	products := int64(2)
+	none := int64(0)
+
+	tests := []struct {
+		cat  catalogs.Catalog
+		want bool
+	}{
+		{catalogs.Catalog{}, false},
+		{catalogs.Catalog{NumProductsWork: &none}, false},
+		{catalogs.Catalog{NumProductsWork: &products}, true},
+		{catalogs.Catalog{LastImported: &earlier}, true},
+		{catalogs.Catalog{LastImported: &later, LastPublished: &earlier, PublishedVersion: &version}, true},
+		{catalogs.Catalog{LastImported: &earlier, LastPublished: &later, PublishedVersion: &version}, false},
+		{catalogs.Catalog{LastPublished: &later, PublishedVersion: &version, NumProductsWork: &products}, false},
+	}
+	for i, tt := range tests {
+		if got := tt.cat.PublishPending(); got != tt.want {
+			t.Errorf("#%d: expected %v; got: %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestCatalogNeedsPublish(t *testing.T) {
+	for file, want := range map[string]bool{
+		"catalogs.get.success":   true,
+		"catalogs.get.published": false,
+	} {
+		service, ts, err := getService(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := service.NeedsPublish(context.Background(), "5094310527")
+		ts.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if got != want {
+			t.Errorf("%s: expected %v; got: %v", file, want, got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// UnmarshalJSON decodes a catalog. Its timestamps are parsed tolerantly,
+// i.e. they may have other formats than RFC 3339 as well, e.g. no
+// fractional seconds or no time zone.
+func (c *Catalog) UnmarshalJSON(data []byte) error {
+	type catalog Catalog
+	return meplatoapi.UnmarshalTimes(data, (*catalog)(c))
+}
+
+// UnmarshalJSON decodes a KPI summary. CreatedAt is parsed tolerantly like
+// the timestamps of a Catalog.
+func (k *KPISummary) UnmarshalJSON(data []byte) error {
+	type kpiSummary KPISummary
+	return meplatoapi.UnmarshalTimes(data, (*kpiSummary)(k))
+}
+
+// UnmarshalJSON decodes a project. Created and Updated are parsed
+// tolerantly like the timestamps of a Catalog.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+	return meplatoapi.UnmarshalTimes(data, (*project)(p))
+}
@@ -0,0 +1,49 @@
+package catalogs_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+func TestCatalogUnmarshalTimes(t *testing.T) {
+	data := `{
+		"pin": "AD8CCDD5F9",
+		"created": "2015-03-31T14:54:37",
+		"updated": "2015-03-31 14:54:37.5",
+		"lastImported": "2015-03-31T16:54:37+0200",
+		"lastPublished": "2015-03-31",
+		"kpiSummary": {"createdAt": "2015-03-31 14:54:37"},
+		"project": {"created": "2015-03-31T14:54:37Z", "updated": "2015-03-31"}
+	}`
+	var c catalogs.Catalog
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.PIN != "AD8CCDD5F9" {
+		t.Errorf("expected PIN %q; got: %q", "AD8CCDD5F9", c.PIN)
+	}
+	at := time.Date(2015, 3, 31, 14, 54, 37, 0, time.UTC)
+	date := time.Date(2015, 3, 31, 0, 0, 0, 0, time.UTC)
+	for name, tt := range map[string]struct {
+		Got  *time.Time
+		Want time.Time
+	}{
+		"created":       {c.Created, at},
+		"updated":       {c.Updated, at.Add(500 * time.Millisecond)},
+		"lastImported":  {c.LastImported, at},
+		"lastPublished": {c.LastPublished, date},
+	} {
+		if tt.Got == nil || !tt.Got.Equal(tt.Want) {
+			t.Errorf("expected %s %v; got: %v", name, tt.Want, tt.Got)
+		}
+	}
+	if c.KpiSummary == nil || !c.KpiSummary.CreatedAt.Equal(at) {
+		t.Errorf("expected KPI summary created at %v; got: %+v", at, c.KpiSummary)
+	}
+	if p := c.Project; p == nil || p.Created == nil || !p.Created.Equal(at) || p.Updated == nil || !p.Updated.Equal(date) {
+		t.Errorf("expected project times to be decoded; got: %+v", c.Project)
+	}
+}
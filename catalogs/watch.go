@@ -0,0 +1,48 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package catalogs
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultWatchInterval is the poll interval used by WatchState if no
+// positive interval is given.
+const DefaultWatchInterval = 5 * time.Second
+
+// WatchState polls the catalog with the given PIN every poll interval
+// until its state equals target, e.g. "idle" after an import finished.
+// It returns the catalog in its final state. If ctx is done before the
+// catalog reaches the target state, WatchState returns the context error.
+func (s *Service) WatchState(ctx context.Context, pin, target string, poll time.Duration) (*Catalog, error) {
+	if poll <= 0 {
+		poll = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		cat, err := s.Get().PIN(pin).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if cat.State == target {
+			return cat, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
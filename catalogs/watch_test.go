@@ -0,0 +1,52 @@
+package catalogs_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCatalogWatchState(t *testing.T) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		if requests < 3 {
+			return "catalogs.get.importing"
+		}
+		return "catalogs.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	cat, err := service.WatchState(context.Background(), "AD8CCDD5F9", "idle", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat.State != "idle" {
+		t.Errorf("expected state %q; got: %q", "idle", cat.State)
+	}
+	if requests != 3 {
+		t.Errorf("expected %d requests; got: %d", 3, requests)
+	}
+}
+
+func TestCatalogWatchStateCanceled(t *testing.T) {
+	service, ts, err := getService("catalogs.get.importing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = service.WatchState(ctx, "AD8CCDD5F9", "idle", time.Millisecond)
+	if err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("expected context to be done; got: %v", err)
+	}
+}
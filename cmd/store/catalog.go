@@ -5,16 +5,22 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"text/template"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
 )
 
 // catalogCommand gets details about one catalog.
 type catalogCommand struct {
+	template string
 }
 
 func init() {
 	RegisterCommand("catalog", func(flags *flag.FlagSet) Command {
 		cmd := new(catalogCommand)
+		flags.StringVar(&cmd.template, "template", "", "Go template to print every catalog with, e.g. '{{.PIN}} {{.Name}}'")
 		return cmd
 	})
 }
@@ -25,12 +31,14 @@ func (c *catalogCommand) Describe() string {
 
 func (c *catalogCommand) Usage() {
 	fmt.Fprintf(os.Stderr, "Usage: %s catalog <pin>\n", os.Args[0])
+	fmt.Fprint(os.Stderr, templateUsage)
 }
 
 func (c *catalogCommand) Examples() []string {
 	return []string{
 		"ABCDE12345",
 		"ABCDE12345 BEEF1C0DE1",
+		"-template='{{.PIN}} {{.Name}} {{.State}}' ABCDE12345",
 	}
 }
 
@@ -44,32 +52,53 @@ func (c *catalogCommand) Run(args []string) error {
 		return err
 	}
 
-	for i, pin := range args {
-		c, err := service.Get().PIN(pin).Do(context.Background())
+	return c.show(context.Background(), service, args, os.Stdout)
+}
+
+// show prints the catalogs with the given PINs to w. If a template is
+// set, it prints every catalog with the template instead of the details.
+func (c *catalogCommand) show(ctx context.Context, service *catalogs.Service, pins []string, w io.Writer) error {
+	var tmpl *template.Template
+	if c.template != "" {
+		var err error
+		if tmpl, err = parseTemplate(c.template); err != nil {
+			return err
+		}
+	}
+
+	for i, pin := range pins {
+		cat, err := service.Get().PIN(pin).Do(ctx)
 		if err != nil {
 			return err
 		}
 
+		if tmpl != nil {
+			if err := tmpl.Execute(w, cat); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if i > 0 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
-		fmt.Printf("%20s: %s\n", "PIN", c.PIN)
-		fmt.Printf("%20s: %s\n", "Name", c.Name)
-		fmt.Printf("%20s: %v\n", "Created", c.Created)
-		if c.NumProductsWork != nil {
-			fmt.Printf("%20s: %d\n", "# products work", *c.NumProductsWork)
+		fmt.Fprintf(w, "%20s: %s\n", "PIN", cat.PIN)
+		fmt.Fprintf(w, "%20s: %s\n", "Name", cat.Name)
+		fmt.Fprintf(w, "%20s: %v\n", "Created", cat.Created)
+		if cat.NumProductsWork != nil {
+			fmt.Fprintf(w, "%20s: %d\n", "# products work", *cat.NumProductsWork)
 		} else {
-			fmt.Printf("%20s: %d\n", "# products work", 0)
+			fmt.Fprintf(w, "%20s: %d\n", "# products work", 0)
 		}
-		if c.NumProductsLive != nil {
-			fmt.Printf("%20s: %d\n", "# products live", *c.NumProductsLive)
+		if cat.NumProductsLive != nil {
+			fmt.Fprintf(w, "%20s: %d\n", "# products live", *cat.NumProductsLive)
 		} else {
-			fmt.Printf("%20s: %d\n", "# products live", 0)
+			fmt.Fprintf(w, "%20s: %d\n", "# products live", 0)
 		}
-		if kpi := c.KpiSummary; kpi != nil {
-			fmt.Printf("%20s: %.2f%%\n", "KPI score", kpi.FinalResult*100)
+		if kpi := cat.KpiSummary; kpi != nil {
+			fmt.Fprintf(w, "%20s: %.2f%%\n", "KPI score", kpi.FinalResult*100)
 		} else {
-			fmt.Printf("%20s: n/a\n", "KPI score")
+			fmt.Fprintf(w, "%20s: n/a\n", "KPI score")
 		}
 	}
 
@@ -3,17 +3,24 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/template"
 	"unicode/utf8"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
 )
 
 // catalogsCommand lists your catalogs.
 type catalogsCommand struct {
 	take, skip int64
 	sort       string
+	counts     bool
+	template   string
 }
 
 func init() {
@@ -22,6 +29,8 @@ func init() {
 		flags.Int64Var(&cmd.take, "take", 0, "Number of catalogs to take")
 		flags.Int64Var(&cmd.skip, "skip", 0, "Number of catalogs to skip")
 		flags.StringVar(&cmd.sort, "sort", "", "Sort order, e.g. name or id or -created")
+		flags.BoolVar(&cmd.counts, "counts", false, "Print the number of products in the work and live area (one extra request per catalog)")
+		flags.StringVar(&cmd.template, "template", "", "Go template to print every catalog with, e.g. '{{.PIN}} {{.Name}}'")
 		return cmd
 	})
 }
@@ -32,6 +41,7 @@ func (c *catalogsCommand) Describe() string {
 
 func (c *catalogsCommand) Usage() {
 	fmt.Fprintf(os.Stderr, "Usage: %s catalogs\n", os.Args[0])
+	fmt.Fprint(os.Stderr, templateUsage)
 }
 
 func (c *catalogsCommand) Examples() []string {
@@ -39,6 +49,8 @@ func (c *catalogsCommand) Examples() []string {
 		"-take=5",
 		"-take=5 -skip=5",
 		"-sort=-created,id",
+		"-counts",
+		"-template='{{.PIN}} {{.Name}} {{.State}}'",
 	}
 }
 
@@ -47,6 +59,23 @@ func (c *catalogsCommand) Run(args []string) error {
 	if err != nil {
 		return err
 	}
+	return c.list(context.Background(), service, os.Stdout)
+}
+
+// list prints the catalogs to w. If counts is set, it gets the number of
+// products of every catalog listed. If a template is set, it prints every
+// catalog with the template instead of the table.
+func (c *catalogsCommand) list(ctx context.Context, service *catalogs.Service, w io.Writer) error {
+	var tmpl *template.Template
+	if c.template != "" {
+		if c.counts {
+			return errors.New("-counts cannot be combined with -template")
+		}
+		var err error
+		if tmpl, err = parseTemplate(c.template); err != nil {
+			return err
+		}
+	}
 
 	svc := service.Search()
 	if c.skip > 0 {
@@ -57,18 +86,49 @@ func (c *catalogsCommand) Run(args []string) error {
 	}
 	svc = svc.Sort(c.sort)
 
-	res, err := svc.Do(context.Background())
+	res, err := svc.Do(ctx)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%d catalogs found.\n", res.TotalItems)
-	fmt.Printf("%3s  %-50s %-10s %-10s\n", "ID", "Name", "Created", "PIN")
-	fmt.Printf("%s\n", strings.Repeat("=", 78))
-	for _, cat := range res.Items {
-		fmt.Printf("%3d. %-50s %-10s %-10s\n", cat.ID, substring(cat.Name, 50), cat.Created.Format("2006-01-02"), cat.PIN)
+	if tmpl != nil {
+		for _, cat := range res.Items {
+			if err := tmpl.Execute(w, cat); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
+	if !c.counts {
+		fmt.Fprintf(w, "%d catalogs found.\n", res.TotalItems)
+		fmt.Fprintf(w, "%3s  %-50s %-10s %-10s\n", "ID", "Name", "Created", "PIN")
+		fmt.Fprintf(w, "%s\n", strings.Repeat("=", 78))
+		for _, cat := range res.Items {
+			fmt.Fprintf(w, "%3d. %-50s %-10s %-10s\n", cat.ID, substring(cat.Name, 50), cat.Created.Format("2006-01-02"), cat.PIN)
+		}
+		return nil
+	}
+
+	pins := make([]string, len(res.Items))
+	for i, cat := range res.Items {
+		pins[i] = cat.PIN
+	}
+	counts, err := service.Counts().PINs(pins...).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%d catalogs found.\n", res.TotalItems)
+	fmt.Fprintf(w, "%3s  %-40s %-10s %-10s %8s %8s\n", "ID", "Name", "Created", "PIN", "Work", "Live")
+	fmt.Fprintf(w, "%s\n", strings.Repeat("=", 86))
+	for _, cat := range res.Items {
+		var work, live int64
+		if n := counts.Items[cat.PIN]; n != nil {
+			work, live = n.Work, n.Live
+		}
+		fmt.Fprintf(w, "%3d. %-40s %-10s %-10s %8d %8d\n", cat.ID, substring(cat.Name, 40), cat.Created.Format("2006-01-02"), cat.PIN, work, live)
+	}
 	return nil
 }
 
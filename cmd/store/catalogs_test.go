@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+// getCatalogsService returns a catalogs service backed by a test server
+// that replays the search and get fixtures of the catalogs package. It
+// counts the requests to get a single catalog in gets.
+func getCatalogsService(t *testing.T, gets *int) (*catalogs.Service, func()) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		if r.URL.Path == "/catalogs" {
+			return "../../catalogs/testdata/catalogs.search.success"
+		}
+		*gets++
+		return "../../catalogs/testdata/catalogs.get.success"
+	})
+	service, err := catalogs.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+	return service, ts.Close
+}
+
+func TestCatalogsList(t *testing.T) {
+	var gets int
+	service, closer := getCatalogsService(t, &gets)
+	defer closer()
+
+	var buf bytes.Buffer
+	if err := new(catalogsCommand).list(context.Background(), service, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if gets != 0 {
+		t.Errorf("expected no extra requests without -counts; got: %d", gets)
+	}
+	if strings.Contains(buf.String(), "Work") {
+		t.Errorf("expected no counts without -counts; got:\n%s", buf.String())
+	}
+}
+
+func TestCatalogsListCounts(t *testing.T) {
+	var gets int
+	service, closer := getCatalogsService(t, &gets)
+	defer closer()
+
+	var buf bytes.Buffer
+	cmd := &catalogsCommand{counts: true}
+	if err := cmd.list(context.Background(), service, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if gets != 2 {
+		t.Errorf("expected %d extra requests with -counts; got: %d", 2, gets)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected %d lines; got:\n%s", 5, buf.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[len(fields)-2] != "Work" || fields[len(fields)-1] != "Live" {
+		t.Errorf("expected Work and Live columns; got: %q", lines[1])
+	}
+	for _, line := range lines[3:] {
+		if fields := strings.Fields(line); fields[len(fields)-2] != "2" || fields[len(fields)-1] != "0" {
+			t.Errorf("expected counts 2 and 0; got: %q", line)
+		}
+	}
+}
+
+func TestCatalogsListTemplate(t *testing.T) {
+	var gets int
+	service, closer := getCatalogsService(t, &gets)
+	defer closer()
+
+	var buf bytes.Buffer
+	cmd := &catalogsCommand{template: "{{.PIN}} {{.Name}} {{.State}}"}
+	if err := cmd.list(context.Background(), service, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "AD8CCDD5F9 Demo-Katalog idle\n5094310527 Ersatzteile idle\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected output %q; got: %q", want, got)
+	}
+}
+
+func TestCatalogsListTemplateInvalid(t *testing.T) {
+	var gets int
+	service, closer := getCatalogsService(t, &gets)
+	defer closer()
+
+	tests := []*catalogsCommand{
+		{template: "{{.PIN"},
+		{template: "{{.Unknown}}"},
+		{template: "{{.PIN}}", counts: true},
+	}
+	for i, cmd := range tests {
+		if err := cmd.list(context.Background(), service, new(bytes.Buffer)); err == nil {
+			t.Errorf("%d. expected error for template %q; got: nil", i, cmd.template)
+		}
+	}
+}
+
+func TestCatalogShowTemplate(t *testing.T) {
+	var gets int
+	service, closer := getCatalogsService(t, &gets)
+	defer closer()
+
+	var buf bytes.Buffer
+	cmd := &catalogCommand{template: "{{.PIN}}: {{.Name}}\n"}
+	if err := cmd.show(context.Background(), service, []string{"5094310527", "5094310527"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "5094310527: Ersatzteile\n5094310527: Ersatzteile\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected output %q; got: %q", want, got)
+	}
+}
+
+func TestCatalogShow(t *testing.T) {
+	var gets int
+	service, closer := getCatalogsService(t, &gets)
+	defer closer()
+
+	var buf bytes.Buffer
+	if err := new(catalogCommand).show(context.Background(), service, []string{"5094310527"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Name: Ersatzteile") {
+		t.Errorf("expected catalog details; got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDNSCache(t *testing.T) {
+	var lookups int
+	c := newDNSCache(time.Hour)
+	c.lookup = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"127.0.0.1"}, nil
+	}
+	for i := 0; i < 3; i++ {
+		addrs, err := c.LookupHost(context.Background(), "store.meplato.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(addrs) != 1 || addrs[0] != "127.0.0.1" {
+			t.Fatalf("expected [127.0.0.1]; got: %v", addrs)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected %d lookup; got: %d", 1, lookups)
+	}
+
+	c.ttl = 0
+	c.entries = make(map[string]dnsEntry)
+	c.LookupHost(context.Background(), "store.meplato.com")
+	c.LookupHost(context.Background(), "store.meplato.com")
+	if lookups != 3 {
+		t.Fatalf("expected %d lookups after expiry; got: %d", 3, lookups)
+	}
+}
+
+func TestGetMaxConnsPerHost(t *testing.T) {
+	t.Setenv("STORE_MAX_CONNS_PER_HOST", "32")
+	if n, err := getMaxConnsPerHost(); err != nil || n != 32 {
+		t.Fatalf("expected %d; got: %d (%v)", 32, n, err)
+	}
+	t.Setenv("STORE_MAX_CONNS_PER_HOST", "many")
+	if _, err := getMaxConnsPerHost(); err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+}
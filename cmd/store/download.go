@@ -8,6 +8,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+	"github.com/meplato/store2-go-client/v2/products"
 )
 
 // downloadCommand downloads a specific catalog.
@@ -15,14 +19,31 @@ type downloadCommand struct {
 	verbose bool
 	area    string
 	outfile string
+	search  bool
+	sort    string
+	workers int
+	mode    string
+	version int64
 }
 
+// downloadPageSize is the number of products per page in search mode.
+const downloadPageSize = 100
+
+// pinPlaceholder is replaced by the PIN of the catalog in the name of the
+// output file when downloading several catalogs.
+const pinPlaceholder = "{pin}"
+
 func init() {
 	RegisterCommand("download", func(flags *flag.FlagSet) Command {
 		cmd := new(downloadCommand)
 		flags.BoolVar(&cmd.verbose, "v", false, "Print progress")
 		flags.StringVar(&cmd.area, "area", "live", "Area to download (work/live)")
-		flags.StringVar(&cmd.outfile, "o", "", "Output file")
+		flags.StringVar(&cmd.outfile, "o", "", "Output file; use {pin} in the name when downloading several catalogs")
+		flags.BoolVar(&cmd.search, "search", false, "Page through search results instead of scrolling")
+		flags.StringVar(&cmd.sort, "sort", "", "Sort order, e.g. name or -created (implies -search)")
+		flags.IntVar(&cmd.workers, "workers", 4, "Number of catalogs to download concurrently")
+		flags.StringVar(&cmd.mode, "mode", "", "Scroll mode (full/diff); diff requires -version")
+		flags.Int64Var(&cmd.version, "version", 0, "Version of the catalog to download")
 		return cmd
 	})
 }
@@ -32,18 +53,51 @@ func (c *downloadCommand) Describe() string {
 }
 
 func (c *downloadCommand) Usage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s download\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Usage: %s download <pin> [<pin>...]\n", os.Args[0])
+	fmt.Fprint(os.Stderr, `
+By default, download scrolls through all products of the catalog. Scrolling
+is the fastest way to download big catalogs, but the order of the products
+is unspecified and cannot be changed.
+
+Use -sort to download the products in a specific order. It pages through
+the search results instead of scrolling, which takes more requests. The
+sort order is a comma-separated list of name, spn, id, and created, each
+optionally prefixed by a minus sign for descending order, e.g. -created.
+Use -search to page through the search results in their default order.
+
+Changes since a version:
+
+Use -mode=diff together with -version to download only the products that
+changed with the given version of the catalog, e.g. for incremental syncs.
+The output then has an additional Mode column with the type of change of
+each product, i.e. Created, Updated, or Deleted. Use -mode=full to download
+all products of a specific version. Both require scrolling and cannot be
+combined with -search or -sort.
+
+Several catalogs:
+
+Pass several PINs to download the catalogs concurrently, e.g. for nightly
+backups. Each catalog is written to its own file. The name of the file is
+given by -o, where {pin} is replaced by the PIN of the catalog, e.g.
+-o backup/{pin}.csv. It defaults to {pin}.csv. Use -workers to set how
+many catalogs are downloaded at the same time. Download prints a summary
+for every catalog and exits with a non-zero code if any download failed.
+
+`)
 }
 
 func (c *downloadCommand) Examples() []string {
 	return []string{
 		"ABCDE12345 -v",
 		"ABCDE12345 -o catalog.out",
+		"-sort spn ABCDE12345",
+		"-mode diff -version 42 -o changes.csv ABCDE12345",
+		"-workers 2 -o backup/{pin}.csv ABCDE12345 FGHIJ67890 KLMNO24680",
 	}
 }
 
 func (c *downloadCommand) Run(args []string) error {
-	if len(args) != 1 {
+	if len(args) == 0 {
 		return errors.New("no pin specified")
 	}
 
@@ -52,6 +106,10 @@ func (c *downloadCommand) Run(args []string) error {
 		return err
 	}
 
+	if len(args) > 1 {
+		return c.downloadAll(context.Background(), service, args, os.Stdout)
+	}
+
 	var out io.Writer
 	if c.outfile != "" {
 		f, err := os.OpenFile(c.outfile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
@@ -64,23 +122,70 @@ func (c *downloadCommand) Run(args []string) error {
 		out = os.Stdout
 	}
 
+	n, err := c.download(context.Background(), service, args[0], out)
+	if err != nil {
+		return err
+	}
+
+	if c.verbose {
+		fmt.Fprintf(os.Stdout, "Downloaded %d products\n", n)
+	}
+
+	return nil
+}
+
+// download writes the products of the catalog with the given PIN to out
+// and returns the number of products written.
+func (c *downloadCommand) download(ctx context.Context, service *products.Service, pin string, out io.Writer) (int, error) {
+	if err := c.validateMode(); err != nil {
+		return 0, err
+	}
+	diff := c.mode == "diff"
+
+	var it *products.Iterator
+	if c.search || c.sort != "" {
+		if err := products.ValidateSort(c.sort); err != nil {
+			return 0, err
+		}
+		search := service.Search().PIN(pin).Area(c.area).Take(downloadPageSize)
+		if c.sort != "" {
+			search = search.Sort(c.sort)
+		}
+		it = search.Iterator()
+	} else {
+		scroll := service.Scroll().PIN(pin).Area(c.area)
+		if c.mode != "" {
+			scroll = scroll.Mode(c.mode)
+		}
+		if c.version > 0 {
+			scroll = scroll.Version(c.version)
+		}
+		it = scroll.Iterator()
+	}
+
 	csvw := csv.NewWriter(out)
 	csvw.Comma = ';'
 	csvw.UseCRLF = true
-	_ = csvw.Write([]string{"Supplier SKU", "Name", "Price", "Price Qty", "Currency", "Order unit", "Manufacturer", "Manufacturer SKU", "GTIN/EAN"})
+	header := []string{"Supplier SKU", "Name", "Price", "Price Qty", "Currency", "Order unit", "Manufacturer", "Manufacturer SKU", "GTIN/EAN"}
+	if diff {
+		header = append(header, "Mode")
+	}
+	_ = csvw.Write(header)
 
 	var n int
-	var pageToken string
 	for {
-		res, err := service.Scroll().PIN(args[0]).Area(c.area).PageToken(pageToken).Do(context.Background())
+		items, ok, err := it.Next(ctx)
 		if err != nil {
-			return err
+			return n, err
+		}
+		if !ok {
+			break
 		}
 
-		for _, item := range res.Items {
+		for _, item := range items {
 			n++
 
-			csvw.Write([]string{
+			record := []string{
 				item.Spn,
 				item.Name,
 				fmt.Sprintf("%.2f", item.Price),
@@ -90,20 +195,112 @@ func (c *downloadCommand) Run(args []string) error {
 				item.Manufacturer,
 				item.Mpn,
 				item.Gtin,
-			})
+			}
+			if diff {
+				record = append(record, formatMode(item.Mode))
+			}
+			csvw.Write(record)
 		}
-
-		if res.PageToken == "" {
-			break
-		}
-		pageToken = res.PageToken
 	}
 
 	csvw.Flush()
+	return n, csvw.Error()
+}
 
-	if c.verbose {
-		fmt.Fprintf(os.Stdout, "Downloaded %d products\n", n)
+// validateMode checks the combination of -mode, -version, and the
+// options for paging through search results.
+func (c *downloadCommand) validateMode() error {
+	switch c.mode {
+	case "", "full", "diff":
+	default:
+		return fmt.Errorf("invalid mode %q; use full or diff", c.mode)
+	}
+	if c.version < 0 {
+		return fmt.Errorf("invalid version %d", c.version)
+	}
+	if c.mode == "diff" && c.version == 0 {
+		return errors.New("mode diff requires a version")
+	}
+	if (c.mode != "" || c.version > 0) && (c.search || c.sort != "") {
+		return errors.New("mode and version cannot be combined with search or sort")
+	}
+	return nil
+}
+
+// formatMode formats the type of change of a product in a differential
+// download, e.g. CREATED, as Created.
+func formatMode(mode string) string {
+	if mode == "" {
+		return ""
+	}
+	return strings.ToUpper(mode[:1]) + strings.ToLower(mode[1:])
+}
+
+// downloadResult is the outcome of downloading a single catalog.
+type downloadResult struct {
+	PIN      string
+	Filename string
+	Products int
+	Err      error
+}
+
+// downloadAll downloads the catalogs with the given PINs concurrently,
+// each into the file named by the outfile template, and prints a summary
+// for every catalog to w. It returns an error if any download failed.
+func (c *downloadCommand) downloadAll(ctx context.Context, service *products.Service, pins []string, w io.Writer) error {
+	template := c.outfile
+	if template == "" {
+		template = pinPlaceholder + ".csv"
+	}
+	if !strings.Contains(template, pinPlaceholder) {
+		return fmt.Errorf("output file %q must contain %s when downloading several catalogs", template, pinPlaceholder)
 	}
 
+	var results []*downloadResult
+	seen := make(map[string]bool)
+	for _, pin := range pins {
+		if !seen[pin] {
+			seen[pin] = true
+			results = append(results, &downloadResult{PIN: pin, Filename: strings.ReplaceAll(template, pinPlaceholder, pin)})
+		}
+	}
+	err := meplatoapi.ForEach(ctx, len(results), c.workers, func(ctx context.Context, i int) {
+		r := results[i]
+		r.Products, r.Err = c.downloadFile(ctx, service, r.PIN, r.Filename)
+	})
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(w, "%-12s failed: %v\n", r.PIN, r.Err)
+		} else {
+			fmt.Fprintf(w, "%-12s %8d products written to %s\n", r.PIN, r.Products, r.Filename)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d catalogs could not be downloaded", failed, len(results))
+	}
 	return nil
 }
+
+// downloadFile downloads the catalog with the given PIN into the named
+// file and returns the number of products written. If the download fails,
+// the incomplete file is removed.
+func (c *downloadCommand) downloadFile(ctx context.Context, service *products.Service, pin, filename string) (int, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.download(ctx, service, pin, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(filename)
+	}
+	return n, err
+}
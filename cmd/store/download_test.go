@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestDownloadSorted(t *testing.T) {
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("skip") == "0" {
+			fmt.Fprint(w, `{"kind":"store#products","totalItems":3,"items":[{"spn":"A"},{"spn":"B"}]}`)
+		} else {
+			fmt.Fprint(w, `{"kind":"store#products","totalItems":3,"items":[{"spn":"C"}]}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	cmd := &downloadCommand{area: "live", sort: "spn"}
+	n, err := cmd.download(context.Background(), service, "AD8CCDD5F9", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected %d products; got: %d", 3, n)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected %d requests; got: %v", 2, queries)
+	}
+	for _, q := range queries {
+		if !strings.Contains(q, "/catalogs/AD8CCDD5F9/live/products?") || !strings.Contains(q, "sort=spn") {
+			t.Errorf("expected sorted search request; got: %s", q)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\r\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected %d lines; got: %q", 4, lines)
+	}
+	for i, spn := range []string{"A", "B", "C"} {
+		if !strings.HasPrefix(lines[i+1], spn+";") {
+			t.Errorf("line %d: expected SPN %q; got: %q", i+2, spn, lines[i+1])
+		}
+	}
+}
+
+func TestDownloadInvalidSort(t *testing.T) {
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := &downloadCommand{area: "live", sort: "price"}
+	if _, err := cmd.download(context.Background(), service, "AD8CCDD5F9", new(bytes.Buffer)); err == nil {
+		t.Fatal("expected error for unsupported sort key; got: nil")
+	}
+}
+
+func TestDownloadDiff(t *testing.T) {
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"store#productsScrollResponse","totalItems":3,"items":[{"spn":"A","mode":"CREATED"},{"spn":"B","mode":"UPDATED"},{"spn":"C","mode":"DELETED"}]}`)
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	cmd := &downloadCommand{area: "live", mode: "diff", version: 42}
+	n, err := cmd.download(context.Background(), service, "AD8CCDD5F9", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected %d products; got: %d", 3, n)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("expected %d request; got: %v", 1, queries)
+	}
+	if q := queries[0]; !strings.Contains(q, "/products/scroll?") || !strings.Contains(q, "mode=diff") || !strings.Contains(q, "version=42") {
+		t.Errorf("expected scroll request in diff mode for version 42; got: %s", q)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\r\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected %d lines; got: %q", 4, lines)
+	}
+	if !strings.HasSuffix(lines[0], ";GTIN/EAN;Mode") {
+		t.Errorf("expected Mode column in header; got: %q", lines[0])
+	}
+	for i, mode := range []string{"Created", "Updated", "Deleted"} {
+		if !strings.HasSuffix(lines[i+1], ";"+mode) {
+			t.Errorf("line %d: expected mode %q; got: %q", i+2, mode, lines[i+1])
+		}
+	}
+}
+
+func TestDownloadInvalidMode(t *testing.T) {
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []*downloadCommand{
+		{area: "live", mode: "partial"},
+		{area: "live", mode: "diff"},
+		{area: "live", mode: "diff", version: -1},
+		{area: "live", mode: "diff", version: 42, sort: "spn"},
+		{area: "live", version: 42, search: true},
+	}
+	for i, cmd := range tests {
+		if _, err := cmd.download(context.Background(), service, "AD8CCDD5F9", new(bytes.Buffer)); err == nil {
+			t.Errorf("%d. expected error for mode %q and version %d; got: nil", i, cmd.mode, cmd.version)
+		}
+	}
+}
+
+func TestDownloadAll(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/unknown/"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":404,"message":"Catalog not found"}}`)
+		case strings.Contains(r.URL.Path, "/AD8CCDD5F9/"):
+			fmt.Fprint(w, `{"kind":"store#products","totalItems":2,"items":[{"spn":"A"},{"spn":"B"}]}`)
+		default:
+			fmt.Fprint(w, `{"kind":"store#products","totalItems":1,"items":[{"spn":"C"}]}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	cmd := &downloadCommand{area: "live", search: true, workers: 2, outfile: filepath.Join(dir, "{pin}.csv")}
+	err = cmd.downloadAll(context.Background(), service, []string{"AD8CCDD5F9", "unknown", "5094310527", "AD8CCDD5F9"}, &buf)
+	if err == nil {
+		t.Fatal("expected error for unknown catalog; got: nil")
+	}
+	if want := "1 of 3 catalogs could not be downloaded"; err.Error() != want {
+		t.Errorf("expected error %q; got: %q", want, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected %d lines; got:\n%s", 3, buf.String())
+	}
+	for i, want := range []string{"AD8CCDD5F9          2 products", "unknown      failed:", "5094310527          1 products"} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("line %d: expected prefix %q; got: %q", i+1, want, lines[i])
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "unknown.csv")); !os.IsNotExist(err) {
+		t.Errorf("expected the file of the failed download to be removed; got: %v", err)
+	}
+	for pin, want := range map[string]int{"AD8CCDD5F9": 3, "5094310527": 2} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, pin+".csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(strings.Split(strings.TrimSpace(string(data)), "\r\n")); got != want {
+			t.Errorf("%s: expected %d lines; got: %d", pin, want, got)
+		}
+	}
+}
+
+func TestDownloadAllRequiresPinPlaceholder(t *testing.T) {
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := &downloadCommand{area: "live", outfile: "catalog.csv"}
+	if err := cmd.downloadAll(context.Background(), service, []string{"A", "B"}, new(bytes.Buffer)); err == nil {
+		t.Fatal("expected error for output file without {pin}; got: nil")
+	}
+}
@@ -2,6 +2,11 @@ package main
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -9,14 +14,26 @@ import (
 	"os/user"
 	"path"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/bgentry/go-netrc/netrc"
 
+	store2 "github.com/meplato/store2-go-client/v2"
 	"github.com/meplato/store2-go-client/v2/catalogs"
 	"github.com/meplato/store2-go-client/v2/products"
 )
 
+var (
+	caCertFile = flag.String("cacert", "", "PEM file with additional CA certificates to verify the server (or STORE_CACERT)")
+	timeout    = flag.String("timeout", "", "Timeout for every request, e.g. 30s; 0 disables it (or STORE_TIMEOUT; default 5m)")
+)
+
+// defaultTimeout is the time a request may take unless configured
+// otherwise via -timeout or STORE_TIMEOUT.
+const defaultTimeout = 5 * time.Minute
+
 func GetBaseURL() string {
 	if url := os.Getenv("STORE_URL"); url != "" {
 		return url
@@ -83,24 +100,152 @@ func getLoginAndPasswordFromNetrc(serviceEndpoint string) (username, password st
 	return
 }
 
+func getCACertFile() string {
+	if *caCertFile != "" {
+		return *caCertFile
+	}
+	return os.Getenv("STORE_CACERT")
+}
+
+// getTLSConfig returns the TLS configuration for the HTTP client. If a CA
+// certificate file is configured, its certificates are added to the system
+// roots and the server certificate is verified against them.
+func getTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+	}
+	if file := getCACertFile(); file != "" {
+		pem, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", file)
+		}
+		config.RootCAs = pool
+		config.InsecureSkipVerify = false
+	}
+	return config, nil
+}
+
+var (
+	httpClient     *http.Client
+	httpClientErr  error
+	httpClientOnce sync.Once
+)
+
+// getMaxConnsPerHost returns the maximum number of connections per host,
+// as configured in STORE_MAX_CONNS_PER_HOST.
+func getMaxConnsPerHost() (int, error) {
+	s := os.Getenv("STORE_MAX_CONNS_PER_HOST")
+	if s == "" {
+		return runtime.GOMAXPROCS(0) + 1, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("STORE_MAX_CONNS_PER_HOST must be a positive number, got %q", s)
+	}
+	return n, nil
+}
+
+// getDNSCacheTTL returns how long DNS lookups are cached, as configured
+// in STORE_DNS_CACHE_TTL. A TTL of 0 disables the cache.
+func getDNSCacheTTL() (time.Duration, error) {
+	s := os.Getenv("STORE_DNS_CACHE_TTL")
+	if s == "" {
+		return time.Minute, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("STORE_DNS_CACHE_TTL must be a duration like 5m, got %q", s)
+	}
+	return ttl, nil
+}
+
+// getTimeout returns the time a request may take, including reading the
+// response body, as configured via -timeout or STORE_TIMEOUT. A timeout of
+// 0 disables it.
+func getTimeout() (time.Duration, error) {
+	s := *timeout
+	if s == "" {
+		s = os.Getenv("STORE_TIMEOUT")
+	}
+	if s == "" {
+		return defaultTimeout, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("timeout must be a duration like 30s, got %q", s)
+	}
+	return d, nil
+}
+
+// explainTimeout adds a hint on how to change the timeout to err if the
+// server did not respond in time.
+func explainTimeout(err error) error {
+	var ne net.Error
+	if err == nil || !errors.As(err, &ne) || !ne.Timeout() {
+		return err
+	}
+	d, _ := getTimeout()
+	return fmt.Errorf("%w\nThe server did not respond within %v. Use -timeout or STORE_TIMEOUT to change the timeout.", err, d)
+}
+
+// GetHttpClient returns the HTTP client shared by all services of the
+// command line client.
 func GetHttpClient() (*http.Client, error) {
+	httpClientOnce.Do(func() {
+		httpClient, httpClientErr = newHttpClient()
+	})
+	return httpClient, httpClientErr
+}
+
+func newHttpClient() (*http.Client, error) {
+	tlsConfig, err := getTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	maxConnsPerHost, err := getMaxConnsPerHost()
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := getDNSCacheTTL()
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := getTimeout()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}
+	maxIdleConns := 100
+	if maxConnsPerHost > maxIdleConns {
+		maxIdleConns = maxConnsPerHost
+	}
+	dial := dialer.DialContext
+	if ttl > 0 {
+		dial = newDNSCache(ttl).DialContext(dialer)
+	}
 	client := &http.Client{
+		Timeout: timeout,
 		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dial,
+			MaxIdleConns:          maxIdleConns,
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
-			MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: true,
-			},
+			MaxIdleConnsPerHost:   maxConnsPerHost,
+			TLSClientConfig:       tlsConfig,
 		},
 	}
 	return client, nil
@@ -139,3 +284,20 @@ func GetProductsService() (*products.Service, error) {
 	service.Password = getPassword()
 	return service, nil
 }
+
+func GetStore2Service() (*store2.Service, error) {
+	client, err := GetHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	service, err := store2.New(client)
+	if err != nil {
+		return nil, err
+	}
+	if url := GetBaseURL(); url != "" {
+		service.BaseURL = url
+	}
+	service.User = getUsername()
+	service.Password = getPassword()
+	return service, nil
+}
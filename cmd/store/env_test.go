@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetTimeout(t *testing.T) {
+	t.Setenv("STORE_TIMEOUT", "")
+	if d, err := getTimeout(); err != nil || d != defaultTimeout {
+		t.Fatalf("expected %v; got: %v (%v)", defaultTimeout, d, err)
+	}
+	t.Setenv("STORE_TIMEOUT", "30s")
+	if d, err := getTimeout(); err != nil || d != 30*time.Second {
+		t.Fatalf("expected %v; got: %v (%v)", 30*time.Second, d, err)
+	}
+	t.Setenv("STORE_TIMEOUT", "0")
+	if d, err := getTimeout(); err != nil || d != 0 {
+		t.Fatalf("expected %v; got: %v (%v)", 0, d, err)
+	}
+	for _, s := range []string{"30", "-1s", "soon"} {
+		t.Setenv("STORE_TIMEOUT", s)
+		if _, err := getTimeout(); err == nil {
+			t.Errorf("%q: expected error; got: nil", s)
+		}
+	}
+}
+
+func TestHttpClientTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	t.Setenv("STORE_TIMEOUT", "50ms")
+	client, err := newHttpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Get(ts.URL)
+	if err == nil {
+		t.Fatal("expected timeout error; got: nil")
+	}
+	err = explainTimeout(err)
+	if !strings.Contains(err.Error(), "did not respond within 50ms") {
+		t.Fatalf("expected error to explain the timeout; got: %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 var (
 	commands     = make(map[string]Command)
 	commandFlags = make(map[string]*flag.FlagSet)
+	aliases      = make(map[string]string)
 )
 
 var ErrUsage = UsageError("invalid command")
@@ -51,6 +52,27 @@ func RegisterCommand(name string, makeCmd func(Flags *flag.FlagSet) Command) {
 	commands[name] = makeCmd(flags)
 }
 
+// RegisterAlias registers alias as another name for the command name.
+// Aliases are not listed in the usage.
+func RegisterAlias(alias, name string) {
+	if _, dup := commands[alias]; dup {
+		log.Fatalf("alias %q conflicts with a command", alias)
+	}
+	if _, dup := aliases[alias]; dup {
+		log.Fatalf("duplicate alias %q registered", alias)
+	}
+	aliases[alias] = name
+}
+
+// commandName returns the name of the command that name refers to, which
+// is name itself unless it is an alias.
+func commandName(name string) string {
+	if target, ok := aliases[name]; ok {
+		return target
+	}
+	return name
+}
+
 func Errorf(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, format, args...)
 }
@@ -113,6 +135,7 @@ For command-specific help:
 
 func help(name string) {
 	executable := os.Args[0]
+	name = commandName(name)
 	cmd := commands[name]
 	cmdFlags := commandFlags[name]
 	cmdFlags.SetOutput(os.Stderr)
@@ -140,7 +163,7 @@ func main() {
 		usage("No command given.")
 	}
 
-	name := args[0]
+	name := commandName(args[0])
 	cmd, ok := commands[name]
 	if !ok {
 		usage(fmt.Sprintf("Unknown command %q", name))
@@ -168,7 +191,7 @@ func main() {
 	}
 
 	if err != nil {
-		Errorf("Error: %v\n", err)
+		Errorf("Error: %v\n", explainTimeout(err))
 		Exit(2)
 	}
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+)
+
+// meCommand shows the merchant and user of your API token.
+type meCommand struct{}
+
+func init() {
+	RegisterCommand("me", func(flags *flag.FlagSet) Command {
+		return new(meCommand)
+	})
+	RegisterAlias("whoami", "me")
+}
+
+func (c *meCommand) Describe() string {
+	return "Show the merchant and user of your API token."
+}
+
+func (c *meCommand) Usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s me (or whoami)\n", os.Args[0])
+}
+
+func (c *meCommand) Run(args []string) error {
+	service, err := GetStore2Service()
+	if err != nil {
+		return err
+	}
+	return c.me(context.Background(), service, os.Stdout)
+}
+
+// me prints the merchant name, MPCC, and user email of the account that
+// service is authenticated as.
+func (c *meCommand) me(ctx context.Context, service *store2.Service, w io.Writer) error {
+	res, err := service.Me().Do(ctx)
+	if err != nil {
+		return err
+	}
+	var merchant store2.Merchant
+	if res.Merchant != nil {
+		merchant = *res.Merchant
+	}
+	var user store2.User
+	if res.User != nil {
+		user = *res.User
+	}
+	fmt.Fprintf(w, "URL:      %s\n", service.BaseURL)
+	fmt.Fprintf(w, "Merchant: %s\n", merchant.Name)
+	fmt.Fprintf(w, "MPCC:     %s\n", merchant.Mpcc)
+	fmt.Fprintf(w, "User:     %s\n", user.Email)
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestMe(t *testing.T) {
+	ts := storetest.ReplayServer("../../testdata/me.success")
+	defer ts.Close()
+	service, err := store2.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	if err := new(meCommand).me(context.Background(), service, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Merchant: ABC Elektronik\n",
+		"MPCC:     abc-elektronik\n",
+		"User:     max.mustermann@meplato.com\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q; got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMeUnauthorized(t *testing.T) {
+	ts := storetest.ReplayServer("../../testdata/me.unauthorized")
+	defer ts.Close()
+	service, err := store2.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	if err := new(meCommand).me(context.Background(), service, &buf); err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+}
+
+func TestWhoamiAlias(t *testing.T) {
+	if _, ok := commands["whoami"]; ok {
+		t.Fatal("expected whoami to be an alias, not a command of its own")
+	}
+	if name := commandName("whoami"); name != "me" {
+		t.Fatalf("expected whoami to refer to %q; got: %q", "me", name)
+	}
+	if name := commandName("me"); name != "me" {
+		t.Fatalf("expected %q; got: %q", "me", name)
+	}
+}
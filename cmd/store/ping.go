@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+)
+
+// pingCommand checks whether the API is reachable.
+type pingCommand struct{}
+
+func init() {
+	RegisterCommand("ping", func(flags *flag.FlagSet) Command {
+		return new(pingCommand)
+	})
+}
+
+func (c *pingCommand) Describe() string {
+	return "Check whether the API is reachable; exits with a non-zero code if not."
+}
+
+func (c *pingCommand) Usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s ping\n", os.Args[0])
+}
+
+func (c *pingCommand) Run(args []string) error {
+	service, err := GetStore2Service()
+	if err != nil {
+		return err
+	}
+	return c.ping(context.Background(), service, os.Stdout)
+}
+
+// ping pings the API and prints the latency. It returns an error if the
+// API is not reachable or rejects the credentials.
+func (c *pingCommand) ping(ctx context.Context, service *store2.Service, w io.Writer) error {
+	started := time.Now()
+	if err := service.Ping().Do(ctx); err != nil {
+		return fmt.Errorf("ping %s failed: %v", service.BaseURL, err)
+	}
+	fmt.Fprintf(w, "%s is reachable (%v)\n", service.BaseURL, time.Since(started).Round(time.Millisecond))
+	return nil
+}
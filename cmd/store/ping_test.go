@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestPing(t *testing.T) {
+	ts := storetest.ReplayServer("../../testdata/ping.success")
+	defer ts.Close()
+	service, err := store2.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	if err := new(pingCommand).ping(context.Background(), service, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := ts.URL + " is reachable ("; !strings.HasPrefix(buf.String(), want) {
+		t.Fatalf("expected output to start with %q; got: %q", want, buf.String())
+	}
+}
+
+func TestPingUnauthorized(t *testing.T) {
+	ts := storetest.ReplayServer("../../testdata/ping.unauthorized")
+	defer ts.Close()
+	service, err := store2.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	err = new(pingCommand).ping(context.Background(), service, &buf)
+	if err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output; got: %q", buf.String())
+	}
+}
@@ -5,65 +5,82 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
 )
 
-// publishCommand publishes a catalog.
+// publishCommand publishes one or more catalogs.
 type publishCommand struct {
+	workers int
+	poll    time.Duration
 }
 
 func init() {
 	RegisterCommand("publish", func(flags *flag.FlagSet) Command {
 		cmd := new(publishCommand)
+		flags.IntVar(&cmd.workers, "workers", catalogs.DefaultBulkPublishWorkers, "Number of catalogs to publish concurrently")
+		flags.DurationVar(&cmd.poll, "poll", catalogs.DefaultWatchInterval, "Interval to check the publish status when publishing several catalogs")
 		return cmd
 	})
 }
 
 func (c *publishCommand) Describe() string {
-	return "Publish a catalog."
+	return "Publish one or more catalogs."
 }
 
 func (c *publishCommand) Usage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s publish <pin>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Usage: %s publish [-workers n] [-poll interval] <pin> [<pin>...]\n", os.Args[0])
 }
 
 func (c *publishCommand) Examples() []string {
 	return []string{
 		"ABCDE12345",
+		"-workers 8 ABCDE12345 FGHIJ67890 KLMNO24680",
+		"-poll 30s ABCDE12345 FGHIJ67890",
 	}
 }
 
 func (c *publishCommand) Run(args []string) error {
-	if len(args) != 1 {
+	if len(args) == 0 {
 		return errors.New("no pin specified")
 	}
 
-	pin := args[0]
-
 	service, err := GetCatalogsService()
 	if err != nil {
 		return err
 	}
 
+	if len(args) > 1 {
+		return c.publishAll(context.Background(), service, args, os.Stdout)
+	}
+
+	pin := args[0]
+
 	// Start publish
-	_, err = service.Publish().PIN(pin).Do(context.Background())
+	ctx := context.Background()
+	_, err = service.Publish().PIN(pin).Do(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Get status every 5 seconds
+	// Get status, polling more slowly over time, but at least every 5 seconds
+	backoff := meplatoapi.Backoff{Initial: time.Second, Max: 5 * time.Second, Jitter: 0.2}
 	for {
-		time.Sleep(5 * time.Second)
+		if err := backoff.Wait(ctx); err != nil {
+			return err
+		}
 
-		status, err := service.PublishStatus().PIN(pin).Do(context.Background())
+		status, err := service.PublishStatus().PIN(pin).Do(ctx)
 		if err != nil {
 			return err
 		}
 
-		fmt.Fprintf(os.Stdout, "Step %6d of %6d   %03d%%\r",
-			status.CurrentStep, status.TotalSteps, status.Percent)
+		fmt.Fprintf(os.Stdout, "%-78s\r", status)
 
 		if status.Done {
 			break
@@ -74,3 +91,25 @@ func (c *publishCommand) Run(args []string) error {
 
 	return nil
 }
+
+// publishAll publishes the catalogs with the given PINs concurrently and
+// prints the outcome for every catalog to w. It returns an error if any
+// of the catalogs could not be published.
+func (c *publishCommand) publishAll(ctx context.Context, service *catalogs.Service, pins []string, w io.Writer) error {
+	fmt.Fprintf(w, "Publishing %d catalogs...\n", len(pins))
+	res, err := service.BulkPublish().PINs(pins...).Workers(c.workers).Poll(c.poll).Do(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range res.Items {
+		if item.Err != nil {
+			fmt.Fprintf(w, "%-12s failed: %v\n", item.PIN, item.Err)
+		} else {
+			fmt.Fprintf(w, "%-12s done\n", item.PIN)
+		}
+	}
+	if failed := res.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d catalogs could not be published", len(failed), len(res.Items))
+	}
+	return nil
+}
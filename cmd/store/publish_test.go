@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestPublishAll(t *testing.T) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/catalogs/unknown/"):
+			return "../../catalogs/testdata/catalogs.get.not_found"
+		case strings.HasSuffix(r.URL.Path, "/publish"):
+			return "../../catalogs/testdata/catalogs.publish.success"
+		}
+		return "../../catalogs/testdata/catalogs.publish.done"
+	})
+	defer ts.Close()
+	service, err := catalogs.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	cmd := &publishCommand{workers: 2, poll: time.Millisecond}
+	err = cmd.publishAll(context.Background(), service, []string{"AD8CCDD5F9", "unknown", "5094310527"}, &buf)
+	if err == nil {
+		t.Fatal("expected error for unknown catalog; got: nil")
+	}
+	if want := "1 of 3 catalogs could not be published"; err.Error() != want {
+		t.Errorf("expected error %q; got: %q", want, err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected %d lines; got:\n%s", 4, buf.String())
+	}
+	for i, prefix := range []string{"AD8CCDD5F9   done", "unknown      failed:", "5094310527   done"} {
+		if !strings.HasPrefix(lines[i+1], prefix) {
+			t.Errorf("#%d: expected line to start with %q; got: %q", i, prefix, lines[i+1])
+		}
+	}
+}
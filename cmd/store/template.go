@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateUsage describes the -template flag of commands that print
+// catalogs.
+const templateUsage = `
+Output templates:
+
+Use -template to print the catalogs in a custom format. The template is
+a Go text/template that is executed for every catalog, e.g.
+-template '{{.PIN}} {{.Name}} {{.State}}'. The fields are those of the
+Catalog type of the catalogs package. A newline is appended to the output
+of every catalog unless the template ends with one.
+
+`
+
+// parseTemplate parses text as a template that prints a single item.
+// A newline is appended unless text already ends with one.
+func parseTemplate(text string) (*template.Template, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return tmpl, nil
+}
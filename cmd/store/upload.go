@@ -1,15 +1,18 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/csv"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/meplato/store2-go-client/v2/products"
@@ -17,8 +20,16 @@ import (
 
 // uploadCommand uploads to a specific catalog.
 type uploadCommand struct {
-	verbose bool
-	infile  string
+	verbose      bool
+	infile       string
+	gzip         bool
+	decimalComma bool
+	batchSize    int
+	stripHTML    bool
+	statefile    string
+	// ignoreUnknownColumns skips columns with unknown names instead of
+	// failing the upload.
+	ignoreUnknownColumns bool
 }
 
 func init() {
@@ -26,6 +37,12 @@ func init() {
 		cmd := new(uploadCommand)
 		flags.BoolVar(&cmd.verbose, "v", false, "Print progress")
 		flags.StringVar(&cmd.infile, "i", "", "Input file")
+		flags.BoolVar(&cmd.gzip, "gzip", false, "Input is gzip-compressed (implied for .gz input files)")
+		flags.BoolVar(&cmd.decimalComma, "decimal-comma", false, "Numbers use a comma as decimal separator, e.g. 1.234,56")
+		flags.IntVar(&cmd.batchSize, "batch", 1, "Number of rows to upload concurrently")
+		flags.BoolVar(&cmd.stripHTML, "strip-html", false, "Remove HTML from DESCRIPTION before uploading")
+		flags.StringVar(&cmd.statefile, "state", "", "State file to record progress in and resume an interrupted upload from")
+		flags.BoolVar(&cmd.ignoreUnknownColumns, "ignore-unknown-columns", false, "Skip columns with unknown names instead of failing")
 		return cmd
 	})
 }
@@ -42,9 +59,36 @@ and (optionally) enclosed by double-quotes. All rows in the CSV file must
 have the same number of columns.
 
 The first line is the header line and must include one or more of the
-following columns: MODE, SPN, NAME, PRICE, ORDER_UNIT, MPN, MANUFACTURER,
-ECLASS_VERSION, ECLASS_CODE, and TAX_CODE.
-The header row must have the two columns MODE and SPN.
+following columns: MODE, SPN, NAME, PRICE, ORDER_UNIT, DESCRIPTION, MPN,
+MANUFACTURER, ECLASS_VERSION, ECLASS_CODE, UNSPSC_VERSION, UNSPSC_CODE,
+TAX_CODE, KEEP_PRICE, PRICE_FORMULA, CU, CU_PER_OU, CONV_NUM, CONV_DENOM,
+COUNTRY, and INCOMPLETE.
+The header row must have the two columns MODE and SPN. Every column may
+appear only once. Other columns fail the upload, unless you use the
+-ignore-unknown-columns flag: it skips them, e.g. columns with internal
+notes, and prints a warning for each.
+
+KEEP_PRICE is a boolean and accepts true/false, 1/0, yes/no, and y/n
+(case insensitive). Empty cells leave KEEP_PRICE and PRICE_FORMULA unset.
+
+INCOMPLETE is a boolean like KEEP_PRICE and marks the product as
+incomplete, i.e. as a draft. Empty cells leave the flag unset, so updates
+do not change it.
+
+A product may belong to several eCl@ss and UNSPSC classifications.
+Separate multiple codes in ECLASS_CODE and UNSPSC_CODE with a vertical
+bar, e.g. 19-01-01-01|19-01-01-02. The version column either has a single
+version for all codes or one version per code, e.g. 5.1|7.0. Both the
+version and the code column must be given.
+
+CU is the content unit, CU_PER_OU the number of content units per order
+unit, and CONV_NUM and CONV_DENOM are the numerator and denominator for
+calculating price quantities. CU_PER_OU, CONV_NUM, and CONV_DENOM are
+numbers. Empty cells leave these fields unset.
+
+COUNTRY is the ISO code of the country/region of origin, e.g. DE. If the
+cell is empty, the product is created with the country/region of the
+catalog, and updates leave the country/region unchanged.
 
 The MODE column of each row must have one of the following values:
 C - The product should be created. The row must have the columns
@@ -67,6 +111,51 @@ product with SPN 2000. The 4th row will update the price and the order unit
 of product 2000 to 0.49 and EA respectively. Finally, the product 1000 is
 deleted from the catalog.
 
+Numbers:
+
+By default, numbers like PRICE use a dot as decimal separator, e.g. 1234.56.
+Use the -decimal-comma flag if your file uses a comma as decimal separator
+and, optionally, a dot to group thousands, e.g. 1.234,56.
+
+Batches:
+
+By default, upload sends one row after the other. Use -batch to upload
+several rows concurrently, e.g. -batch 20, which speeds up big uploads
+considerably. Rows with the same SPN are still uploaded in the order of
+the file. Errors are reported per line as usual.
+
+HTML in descriptions:
+
+If DESCRIPTION contains HTML, e.g. when exported from a web shop, use the
+-strip-html flag to convert it to plain text before uploading. Tags are
+removed, entities like &amp; are decoded, and paragraphs and line breaks
+become new lines.
+
+Resuming uploads:
+
+Use -state to record the progress of the upload in a file, e.g.
+-state upload.state. After every batch, upload writes the number, SPN,
+and mode of the last processed line to the file, along with a checksum
+of the input up to that line. If the upload is interrupted, run the same
+command again: it skips the lines that have already been processed and
+continues after them. Rows that failed with a transient error, e.g. while
+the network was down, are recorded in the file as well and uploaded
+again, while rows that succeeded are never sent twice. If the input has
+changed since the state file was written, upload stops before sending
+any row. The state file is removed when all rows have been processed.
+
+Compressed input:
+
+If the input file ends with .gz, it is decompressed with gzip before
+parsing. Use the -gzip flag to read gzip-compressed data from stdin.
+
+Summary:
+
+Rows that cannot be uploaded do not stop the upload. At the end, upload
+prints the number of created, updated, deleted, and failed rows, along with
+the reason for every failure. It exits with a non-zero exit code if any
+row failed.
+
 Final notes:
 
 The upload command is a very simple example to illustrate interacting with
@@ -81,6 +170,12 @@ func (c *uploadCommand) Examples() []string {
 	return []string{
 		"-v ABCDE12345 < catalogfile.csv",
 		"-i catalogdata.csv ABCDE12345",
+		"-i catalogdata.csv.gz ABCDE12345",
+		"-gzip ABCDE12345 < catalogfile.csv.gz",
+		"-batch 20 -i catalogdata.csv ABCDE12345",
+		"-strip-html -i catalogdata.csv ABCDE12345",
+		"-state upload.state -i catalogdata.csv ABCDE12345",
+		"-ignore-unknown-columns -i catalogdata.csv ABCDE12345",
 	}
 }
 
@@ -97,60 +192,145 @@ func (c *uploadCommand) Run(args []string) error {
 	}
 
 	// Prepare input
-	var in io.Reader
-	if c.infile != "" {
-		f, err := os.Open(c.infile)
-		if err != nil {
-			return err
+	in, err := openInput(c.infile, c.gzip)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	res, err := c.upload(context.Background(), service, pin, in)
+	if err != nil {
+		return err
+	}
+	res.PrintSummary(os.Stdout)
+	if len(res.Failed) > 0 {
+		return fmt.Errorf("%d of %d rows failed", len(res.Failed), res.Rows())
+	}
+	return nil
+}
+
+// uploadResult summarizes the outcome of an upload.
+type uploadResult struct {
+	Created int
+	Updated int
+	Deleted int
+	Skipped int
+	Failed  []uploadFailure
+	// Ignored are the names of the unknown columns that were skipped.
+	Ignored []string
+}
+
+// uploadFailure describes a row that could not be uploaded.
+type uploadFailure struct {
+	Line   int
+	SPN    string
+	Reason string
+}
+
+// Rows returns the total number of rows processed. Rows skipped because
+// they were processed by an earlier upload are not included.
+func (r *uploadResult) Rows() int {
+	return r.Created + r.Updated + r.Deleted + len(r.Failed)
+}
+
+// PrintSummary writes a human-readable summary of the upload to w.
+func (r *uploadResult) PrintSummary(w io.Writer) {
+	fmt.Fprintf(w, "Created: %d\n", r.Created)
+	fmt.Fprintf(w, "Updated: %d\n", r.Updated)
+	fmt.Fprintf(w, "Deleted: %d\n", r.Deleted)
+	if r.Skipped > 0 {
+		fmt.Fprintf(w, "Skipped: %d\n", r.Skipped)
+	}
+	fmt.Fprintf(w, "Failed:  %d\n", len(r.Failed))
+	for _, f := range r.Failed {
+		fmt.Fprintf(w, "  line %d: SPN %q: %s\n", f.Line, f.SPN, f.Reason)
+	}
+}
+
+// upload reads the CSV from in and applies every row to the catalog with
+// the given PIN. Rows that fail are recorded in the result and do not stop
+// the upload; errors reading the input do. If a state file is set, rows
+// processed by an earlier upload are skipped.
+func (c *uploadCommand) upload(ctx context.Context, service *products.Service, pin string, in io.Reader) (*uploadResult, error) {
+	var progress *uploadProgress
+	if c.statefile != "" {
+		var err error
+		if progress, err = newUploadProgress(c.statefile, pin); err != nil {
+			return nil, err
+		}
+		if progress.resume != nil && c.verbose {
+			fmt.Fprintf(os.Stdout, "Resuming upload after line %d (SPN %q) with %d pending rows\n", progress.resume.Line, progress.resume.SPN, len(progress.resume.Pending))
 		}
-		defer f.Close()
-		in = f
-	} else {
-		in = os.Stdin
 	}
+
 	csvr := csv.NewReader(in)
 	csvr.Comma = ';'
 
 	// Parse header from input and initialize cell handlers
 	header, err := csvr.Read()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if len(header) == 0 {
-		return errors.New("no header row")
+	handlersByIndex, ignored, err := parseHeader(header, c.ignoreUnknownColumns)
+	if err != nil {
+		return nil, err
 	}
-	handlersByIndex := make(map[int]rowHandler)
-	for i, cell := range header {
-		h, found := rowHandlers[cell]
-		if !found {
-			return fmt.Errorf("found invalid column name %q", cell)
-		}
-		handlersByIndex[i] = h
+	for _, name := range ignored {
+		Errorf("Warning: ignoring unknown column %q\n", name)
+	}
+	if progress != nil {
+		progress.read(header)
 	}
 
-	// Read input file line-by-line
+	// Read input file line-by-line and upload the rows in batches
+	res := &uploadResult{Ignored: ignored}
 	start := time.Now()
 	var line int = 1
+	var batch uploadBatch
+	flush := func() error {
+		c.uploadBatch(ctx, service, pin, batch, res)
+		if progress != nil {
+			if err := progress.advance(batch); err != nil {
+				return err
+			}
+		}
+		batch = nil
+		return nil
+	}
 	for {
 		record, err := csvr.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 		line++
 
-		var r row
-		r.Line = line
-
-		for i, cell := range record {
-			h, found := handlersByIndex[i]
-			if !found {
-				return fmt.Errorf("no handler for index %d", i)
+		r := &row{Line: line, DecimalComma: c.decimalComma}
+		err = parseRow(r, record, handlersByIndex)
+		var checksum string
+		if progress != nil {
+			checksum = progress.read(record)
+			skip, serr := progress.skip(r, checksum)
+			if serr != nil {
+				return nil, serr
 			}
-			if err := h(&r, cell); err != nil {
-				return fmt.Errorf("line %d: %v", line, err)
+			if skip {
+				res.Skipped++
+				continue
+			}
+		}
+		if err == nil && batch.has(r.SPN) {
+			// Keep the order of rows with the same SPN
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		batch = append(batch, &uploadItem{row: r, err: err, checksum: checksum})
+		if len(batch) >= c.batchSize {
+			if err := flush(); err != nil {
+				return nil, err
 			}
 		}
 
@@ -158,91 +338,279 @@ func (c *uploadCommand) Run(args []string) error {
 			pps := int64(float64(line) / time.Since(start).Seconds())
 			fmt.Fprintf(os.Stdout, "line %6d | %04d tx/s\r", line, pps)
 		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		if err := progress.finish(line); err != nil {
+			return nil, err
+		}
+	}
 
-		// Validate the row
-		if err := r.Validate(); err != nil {
-			return fmt.Errorf("line %d: %v", line, err)
+	if c.verbose {
+		pps := int64(float64(line) / time.Since(start).Seconds())
+		fmt.Fprintf(os.Stdout, "Read %d lines in %v (%04d tx/s)\n", line, time.Since(start), pps)
+	}
+
+	return res, nil
+}
+
+// parseHeader returns the row handlers for the columns of the header row.
+// It returns an error if a column appears more than once. Unknown columns
+// are an error as well, unless ignoreUnknown is true: then their cells are
+// skipped, and their names are returned in ignored.
+func parseHeader(header []string, ignoreUnknown bool) (handlersByIndex map[int]rowHandler, ignored []string, err error) {
+	if len(header) == 0 {
+		return nil, nil, errors.New("no header row")
+	}
+	handlersByIndex = make(map[int]rowHandler)
+	columns := make(map[string]int)
+	for i, cell := range header {
+		h, found := rowHandlers[cell]
+		if !found {
+			if !ignoreUnknown {
+				return nil, nil, fmt.Errorf("found invalid column name %q", cell)
+			}
+			ignored = append(ignored, cell)
+			handlersByIndex[i] = handleIgnored
+			continue
+		}
+		if j, dup := columns[cell]; dup {
+			return nil, nil, fmt.Errorf("found duplicate column name %q in columns %d and %d", cell, j+1, i+1)
+		}
+		columns[cell] = i
+		handlersByIndex[i] = h
+	}
+	return handlersByIndex, ignored, nil
+}
+
+// uploadItem is a parsed row that is waiting to be uploaded. err is the
+// error parsing the row, if any. checksum is the checksum of the input up
+// to and including the row, if the upload has a state file.
+type uploadItem struct {
+	row      *row
+	err      error
+	checksum string
+}
+
+// uploadBatch is a list of rows that are uploaded concurrently.
+type uploadBatch []*uploadItem
+
+// has reports whether the batch has a valid row with the given SPN.
+func (b uploadBatch) has(spn string) bool {
+	for _, item := range b {
+		if item.err == nil && item.row.SPN == spn {
+			return true
 		}
+	}
+	return false
+}
+
+// uploadBatch uploads the valid rows of batch concurrently and records
+// the outcome of every row in res, in the order of the batch.
+func (c *uploadCommand) uploadBatch(ctx context.Context, service *products.Service, pin string, batch uploadBatch, res *uploadResult) {
+	var wg sync.WaitGroup
+	for _, item := range batch {
+		if item.err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(item *uploadItem) {
+			defer wg.Done()
+			item.err = c.uploadRow(ctx, service, pin, item.row)
+		}(item)
+	}
+	wg.Wait()
 
-		// Call Create, Update, or Delete API
-		switch r.Mode {
+	for _, item := range batch {
+		if item.err != nil {
+			res.Failed = append(res.Failed, uploadFailure{Line: item.row.Line, SPN: item.row.SPN, Reason: item.err.Error()})
+			continue
+		}
+		switch item.row.Mode {
 		case "C":
-			// Create a new product (or overwrite an existing)
-			p := &products.CreateProduct{
-				Spn:       r.SPN,
-				Name:      *r.Name,
-				Price:     *r.Price,
-				OrderUnit: *r.OrderUnit,
-			}
-			if r.MPN != nil {
-				p.Mpn = *r.MPN
-			}
-			if r.Manufacturer != nil {
-				p.Manufacturer = *r.Manufacturer
-			}
-			if r.EclassVersion != nil && r.EclassCode != nil {
-				p.Eclasses = append(p.Eclasses, &products.Eclass{
-					Version: *r.EclassVersion,
-					Code:    *r.EclassCode,
-				})
-			}
-			if r.TaxCode != nil {
-				p.TaxCode = *r.TaxCode
-			}
-			_, err := service.Create().PIN(pin).Area("work").Product(p).Do(context.Background())
-			if err != nil {
-				return fmt.Errorf("line %d: create failed: %v", line, err)
-			}
+			res.Created++
 		case "U":
-			// Update a product
-			p := &products.UpdateProduct{
-				Name:         r.Name,
-				Price:        r.Price,
-				OrderUnit:    r.OrderUnit,
-				Mpn:          r.MPN,
-				Manufacturer: r.Manufacturer,
-				TaxCode:      r.TaxCode,
-			}
-			if r.EclassVersion != nil && r.EclassCode != nil {
-				p.Eclasses = append(p.Eclasses, &products.Eclass{
-					Version: *r.EclassVersion,
-					Code:    *r.EclassCode,
-				})
-			}
-			_, err := service.Update().PIN(pin).Area("work").Spn(r.SPN).Product(p).Do(context.Background())
-			if err != nil {
-				return fmt.Errorf("line %d: update failed: %v", line, err)
-			}
+			res.Updated++
 		case "D":
-			// Delete a product
-			err := service.Delete().PIN(pin).Area("work").Spn(r.SPN).Do(context.Background())
-			if err != nil {
-				return fmt.Errorf("line %d: delete failed: %v", line, err)
-			}
+			res.Deleted++
 		}
 	}
+}
 
-	if c.verbose {
-		pps := int64(float64(line) / time.Since(start).Seconds())
-		fmt.Fprintf(os.Stdout, "Read %d lines in %v (%04d tx/s)\n", line, time.Since(start), pps)
+// parseRow parses record into r and validates it.
+func parseRow(r *row, record []string, handlersByIndex map[int]rowHandler) error {
+	for i, cell := range record {
+		h, found := handlersByIndex[i]
+		if !found {
+			return fmt.Errorf("no handler for index %d", i)
+		}
+		if err := h(r, cell); err != nil {
+			return err
+		}
+	}
+	return r.Validate()
+}
+
+// uploadRow applies the parsed row r to the catalog.
+func (c *uploadCommand) uploadRow(ctx context.Context, service *products.Service, pin string, r *row) error {
+	eclasses, err := r.Eclasses()
+	if err != nil {
+		return err
+	}
+	unspscs, err := r.Unspscs()
+	if err != nil {
+		return err
 	}
 
+	// Call Create, Update, or Delete API
+	switch r.Mode {
+	case "C":
+		// Create a new product (or overwrite an existing)
+		p := &products.CreateProduct{
+			Spn:       r.SPN,
+			Name:      *r.Name,
+			Price:     *r.Price,
+			OrderUnit: *r.OrderUnit,
+		}
+		if r.Description != nil {
+			p.Description = *r.Description
+		}
+		if r.MPN != nil {
+			p.Mpn = *r.MPN
+		}
+		if r.Manufacturer != nil {
+			p.Manufacturer = *r.Manufacturer
+		}
+		p.Eclasses = eclasses
+		p.Unspscs = unspscs
+		if r.TaxCode != nil {
+			p.TaxCode = *r.TaxCode
+		}
+		p.KeepPrice = r.KeepPrice
+		if r.PriceFormula != nil {
+			p.PriceFormula = *r.PriceFormula
+		}
+		if r.ContentUnit != nil {
+			p.ContentUnit = *r.ContentUnit
+		}
+		p.CuPerOu = r.CuPerOu
+		p.ConversionNumerator = r.ConvNum
+		p.ConversionDenumerator = r.ConvDenom
+		if r.Country != nil {
+			p.Country = *r.Country
+		}
+		p.Incomplete = r.Incomplete
+		create := service.Create().PIN(pin).Area("work").Product(p)
+		if c.stripHTML {
+			create = create.StripHTML()
+		}
+		_, err := create.Do(ctx)
+		if err != nil {
+			return fmt.Errorf("create failed: %w", err)
+		}
+	case "U":
+		// Update a product
+		p := &products.UpdateProduct{
+			Name:                  r.Name,
+			Price:                 r.Price,
+			OrderUnit:             r.OrderUnit,
+			Description:           r.Description,
+			Mpn:                   r.MPN,
+			Manufacturer:          r.Manufacturer,
+			TaxCode:               r.TaxCode,
+			KeepPrice:             r.KeepPrice,
+			PriceFormula:          r.PriceFormula,
+			ContentUnit:           r.ContentUnit,
+			CuPerOu:               r.CuPerOu,
+			ConversionNumerator:   r.ConvNum,
+			ConversionDenumerator: r.ConvDenom,
+			Country:               r.Country,
+			Incomplete:            r.Incomplete,
+			Eclasses:              eclasses,
+			Unspscs:               unspscs,
+		}
+		if c.stripHTML && p.Description != nil {
+			description := products.StripHTML(*p.Description)
+			p.Description = &description
+		}
+		_, err := service.Update().PIN(pin).Area("work").Spn(r.SPN).Product(p).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+	case "D":
+		// Delete a product
+		err := service.Delete().PIN(pin).Area("work").Spn(r.SPN).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("delete failed: %w", err)
+		}
+	}
 	return nil
 }
 
+// openInput opens the named file for reading, or stdin if name is empty.
+// The input is decompressed with gzip if gz is true or the name of the
+// file ends with .gz.
+func openInput(name string, gz bool) (io.ReadCloser, error) {
+	var in io.ReadCloser = ioutil.NopCloser(os.Stdin)
+	if name != "" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		in = f
+	}
+	if !gz && !strings.HasSuffix(strings.ToLower(name), ".gz") {
+		return in, nil
+	}
+	zr, err := gzip.NewReader(in)
+	if err != nil {
+		in.Close()
+		return nil, err
+	}
+	return &gzipReadCloser{Reader: zr, file: in}, nil
+}
+
+// gzipReadCloser closes both the gzip reader and the underlying file.
+type gzipReadCloser struct {
+	*gzip.Reader
+	file io.Closer
+}
+
+func (r *gzipReadCloser) Close() error {
+	err := r.Reader.Close()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
 // row is an intermediary structure to read data into.
 type row struct {
 	Line          int
+	DecimalComma  bool
 	Mode          string
 	SPN           string
 	Name          *string
 	Price         *float64
 	OrderUnit     *string
+	Description   *string
 	MPN           *string
 	Manufacturer  *string
 	EclassVersion *string
 	EclassCode    *string
+	UnspscVersion *string
+	UnspscCode    *string
 	TaxCode       *string
+	KeepPrice     *bool
+	PriceFormula  *string
+	ContentUnit   *string
+	CuPerOu       *float64
+	ConvNum       *float64
+	ConvDenom     *float64
+	Country       *string
+	Incomplete    *bool
 }
 
 // Validate checks for errors in a row. It also ensures that the given
@@ -268,6 +636,63 @@ func (r *row) Validate() error {
 	return nil
 }
 
+// classificationSeparator separates multiple classification codes or
+// versions in a single cell.
+const classificationSeparator = "|"
+
+// Eclasses returns the eCl@ss classifications of the row, if any.
+func (r *row) Eclasses() ([]*products.Eclass, error) {
+	pairs, err := splitClassifications("eCl@ss", r.EclassVersion, r.EclassCode)
+	if err != nil {
+		return nil, err
+	}
+	var eclasses []*products.Eclass
+	for _, pair := range pairs {
+		eclasses = append(eclasses, &products.Eclass{Version: pair[0], Code: pair[1]})
+	}
+	return eclasses, nil
+}
+
+// Unspscs returns the UNSPSC classifications of the row, if any.
+func (r *row) Unspscs() ([]*products.Unspsc, error) {
+	pairs, err := splitClassifications("UNSPSC", r.UnspscVersion, r.UnspscCode)
+	if err != nil {
+		return nil, err
+	}
+	var unspscs []*products.Unspsc
+	for _, pair := range pairs {
+		unspscs = append(unspscs, &products.Unspsc{Version: pair[0], Code: pair[1]})
+	}
+	return unspscs, nil
+}
+
+// splitClassifications splits the version and code cells of a
+// classification system into pairs of version and code. A single version
+// applies to all codes. It returns nil if either cell is empty.
+func splitClassifications(system string, version, code *string) ([][2]string, error) {
+	if version == nil || code == nil {
+		return nil, nil
+	}
+	versions := strings.Split(*version, classificationSeparator)
+	codes := strings.Split(*code, classificationSeparator)
+	if len(versions) != 1 && len(versions) != len(codes) {
+		return nil, fmt.Errorf("found %d %s versions for %d codes", len(versions), system, len(codes))
+	}
+	pairs := make([][2]string, len(codes))
+	for i, c := range codes {
+		v := versions[0]
+		if len(versions) > 1 {
+			v = versions[i]
+		}
+		v, c = strings.TrimSpace(v), strings.TrimSpace(c)
+		if v == "" || c == "" {
+			return nil, fmt.Errorf("found empty %s version or code in %q", system, *code)
+		}
+		pairs[i] = [2]string{v, c}
+	}
+	return pairs, nil
+}
+
 // rowHandler handles the update of a specific cell and writes the parsed
 // value into the field of a row.
 type rowHandler func(r *row, cell string) error
@@ -279,11 +704,27 @@ var rowHandlers = map[string]rowHandler{
 	"NAME":           handleName,
 	"PRICE":          handlePrice,
 	"ORDER_UNIT":     handleOrderUnit,
+	"DESCRIPTION":    handleDescription,
 	"MPN":            handleMPN,
 	"MANUFACTURER":   handleManufacturer,
 	"ECLASS_VERSION": handleEclassVersion,
 	"ECLASS_CODE":    handleEclassCode,
+	"UNSPSC_VERSION": handleUnspscVersion,
+	"UNSPSC_CODE":    handleUnspscCode,
 	"TAX_CODE":       handleTaxCode,
+	"KEEP_PRICE":     handleKeepPrice,
+	"PRICE_FORMULA":  handlePriceFormula,
+	"CU":             handleContentUnit,
+	"CU_PER_OU":      handleCuPerOu,
+	"CONV_NUM":       handleConvNum,
+	"CONV_DENOM":     handleConvDenom,
+	"COUNTRY":        handleCountry,
+	"INCOMPLETE":     handleIncomplete,
+}
+
+// handleIgnored skips the cells of unknown columns.
+func handleIgnored(r *row, cell string) error {
+	return nil
 }
 
 func handleMode(r *row, cell string) error {
@@ -305,7 +746,7 @@ func handleName(r *row, cell string) error {
 
 func handlePrice(r *row, cell string) error {
 	if cell != "" {
-		if price, err := strconv.ParseFloat(cell, 64); err != nil {
+		if price, err := parseDecimal(cell, r.DecimalComma); err != nil {
 			return fmt.Errorf("price %q is not a number", cell)
 		} else {
 			r.Price = &price
@@ -321,6 +762,13 @@ func handleOrderUnit(r *row, cell string) error {
 	return nil
 }
 
+func handleDescription(r *row, cell string) error {
+	if cell != "" {
+		r.Description = &cell
+	}
+	return nil
+}
+
 func handleMPN(r *row, cell string) error {
 	if cell != "" {
 		r.MPN = &cell
@@ -349,9 +797,139 @@ func handleEclassCode(r *row, cell string) error {
 	return nil
 }
 
+func handleUnspscVersion(r *row, cell string) error {
+	if cell != "" {
+		r.UnspscVersion = &cell
+	}
+	return nil
+}
+
+func handleUnspscCode(r *row, cell string) error {
+	if cell != "" {
+		r.UnspscCode = &cell
+	}
+	return nil
+}
+
 func handleTaxCode(r *row, cell string) error {
 	if cell != "" {
 		r.TaxCode = &cell
 	}
 	return nil
 }
+
+func handleKeepPrice(r *row, cell string) error {
+	keepPrice, err := parseBool(cell)
+	if err != nil {
+		return fmt.Errorf("keep price %q is not a boolean", cell)
+	}
+	r.KeepPrice = keepPrice
+	return nil
+}
+
+func handlePriceFormula(r *row, cell string) error {
+	if cell != "" {
+		r.PriceFormula = &cell
+	}
+	return nil
+}
+
+func handleContentUnit(r *row, cell string) error {
+	if cell != "" {
+		r.ContentUnit = &cell
+	}
+	return nil
+}
+
+func handleCuPerOu(r *row, cell string) error {
+	if cell != "" {
+		cuPerOu, err := parseDecimal(cell, r.DecimalComma)
+		if err != nil {
+			return fmt.Errorf("content units per order unit %q is not a number", cell)
+		}
+		r.CuPerOu = &cuPerOu
+	}
+	return nil
+}
+
+func handleConvNum(r *row, cell string) error {
+	if cell != "" {
+		num, err := parseDecimal(cell, r.DecimalComma)
+		if err != nil {
+			return fmt.Errorf("conversion numerator %q is not a number", cell)
+		}
+		r.ConvNum = &num
+	}
+	return nil
+}
+
+func handleConvDenom(r *row, cell string) error {
+	if cell != "" {
+		denom, err := parseDecimal(cell, r.DecimalComma)
+		if err != nil {
+			return fmt.Errorf("conversion denominator %q is not a number", cell)
+		}
+		r.ConvDenom = &denom
+	}
+	return nil
+}
+
+func handleCountry(r *row, cell string) error {
+	if cell != "" {
+		r.Country = &cell
+	}
+	return nil
+}
+
+func handleIncomplete(r *row, cell string) error {
+	incomplete, err := parseBool(cell)
+	if err != nil {
+		return fmt.Errorf("incomplete %q is not a boolean", cell)
+	}
+	r.Incomplete = incomplete
+	return nil
+}
+
+// parseBool parses a boolean cell. It accepts true/false, 1/0, yes/no,
+// and y/n, case insensitive. It returns nil for an empty cell, so that
+// handlers can leave the field unset. Use it for all boolean columns.
+func parseBool(cell string) (*bool, error) {
+	var b bool
+	switch strings.ToLower(strings.TrimSpace(cell)) {
+	case "":
+		return nil, nil
+	case "true", "1", "yes", "y":
+		b = true
+	case "false", "0", "no", "n":
+		b = false
+	default:
+		return nil, fmt.Errorf("invalid boolean %q", cell)
+	}
+	return &b, nil
+}
+
+// parseDecimal parses a number. If decimalComma is true, a comma is used
+// as decimal separator and dots may be used to group thousands, e.g.
+// 1.234,56. Otherwise, a dot is used as decimal separator, e.g. 1234.56.
+func parseDecimal(cell string, decimalComma bool) (float64, error) {
+	cell = strings.TrimSpace(cell)
+	if decimalComma {
+		intPart, fracPart := cell, ""
+		if i := strings.LastIndex(cell, ","); i >= 0 {
+			intPart, fracPart = cell[:i], cell[i+1:]
+		}
+		if groups := strings.Split(intPart, "."); len(groups) > 1 {
+			for _, g := range groups[1:] {
+				if len(g) != 3 {
+					return 0, fmt.Errorf("invalid number %q", cell)
+				}
+			}
+			intPart = strings.Join(groups, "")
+		}
+		cell = intPart
+		if fracPart != "" {
+			cell += "." + fracPart
+		}
+	}
+	return strconv.ParseFloat(cell, 64)
+}
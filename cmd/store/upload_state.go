@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// uploadState is the content of the state file of an upload. It records
+// the last line that has been processed and the lines before it that have
+// to be uploaded again, so that an interrupted upload can be resumed
+// without sending rows twice.
+type uploadState struct {
+	// PIN of the catalog the rows were uploaded to.
+	PIN string `json:"pin"`
+	// Line is the number of the last processed line, including the header.
+	// All rows up to Line have been processed, except those in Pending.
+	Line int `json:"line"`
+	// SPN of the row in Line.
+	SPN string `json:"spn"`
+	// Mode of the row in Line.
+	Mode string `json:"mode"`
+	// Checksum is a SHA-256 checksum of the lines up to and including Line.
+	Checksum string `json:"checksum"`
+	// Pending are the lines up to Line whose rows failed with a transient
+	// error, e.g. because the network was down, in ascending order.
+	Pending []pendingLine `json:"pending,omitempty"`
+	// Updated is the time the state was written.
+	Updated time.Time `json:"updated"`
+}
+
+// pendingLine is a line whose row has to be uploaded again.
+type pendingLine struct {
+	// Line is the number of the line.
+	Line int `json:"line"`
+	// Checksum is a SHA-256 checksum of the lines up to and including Line.
+	Checksum string `json:"checksum"`
+}
+
+// pending returns the index of line in Pending, or -1 if it is not
+// pending.
+func (s *uploadState) pending(line int) int {
+	for i, p := range s.Pending {
+		if p.Line == line {
+			return i
+		}
+	}
+	return -1
+}
+
+// loadUploadState reads the state from the named file. It returns nil if
+// the file does not exist.
+func loadUploadState(filename string) (*uploadState, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := new(uploadState)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", filename, err)
+	}
+	return state, nil
+}
+
+// save writes the state to the named file. It writes to a temporary file
+// first, so that the state file is never left half-written.
+func (s *uploadState) save(filename string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// uploadProgress tracks the progress of an upload in a state file.
+type uploadProgress struct {
+	filename string
+	state    uploadState
+	resume   *uploadState
+	hash     hash.Hash
+}
+
+// newUploadProgress returns the progress of an upload to the catalog with
+// the given PIN, which is written to the named state file. If the state
+// file exists, the upload is resumed after the line recorded in it.
+func newUploadProgress(filename, pin string) (*uploadProgress, error) {
+	resume, err := loadUploadState(filename)
+	if err != nil {
+		return nil, err
+	}
+	if resume != nil && resume.PIN != pin {
+		return nil, fmt.Errorf("state file %s belongs to catalog %s; remove it to upload to %s", filename, resume.PIN, pin)
+	}
+	p := &uploadProgress{
+		filename: filename,
+		state:    uploadState{PIN: pin},
+		resume:   resume,
+		hash:     sha256.New(),
+	}
+	if resume != nil {
+		p.state = *resume
+		p.state.Pending = append([]pendingLine(nil), resume.Pending...)
+	}
+	return p, nil
+}
+
+// read adds a record of the input to the checksum and returns the
+// checksum of all records read so far.
+func (p *uploadProgress) read(record []string) string {
+	for _, cell := range record {
+		p.hash.Write([]byte(cell))
+		p.hash.Write([]byte{0})
+	}
+	p.hash.Write([]byte{'\n'})
+	return hex.EncodeToString(p.hash.Sum(nil))
+}
+
+// skip reports whether the row r, with the checksum of the input up to
+// and including its line, has already been processed by an earlier
+// upload. Pending rows are not skipped. It returns an error if the input
+// has changed since the state file was written.
+func (p *uploadProgress) skip(r *row, checksum string) (bool, error) {
+	if p.resume == nil || r.Line > p.resume.Line {
+		return false, nil
+	}
+	if r.Line == p.resume.Line && (checksum != p.resume.Checksum || r.SPN != p.resume.SPN || r.Mode != p.resume.Mode) {
+		return false, p.changed()
+	}
+	if i := p.resume.pending(r.Line); i >= 0 {
+		if checksum != p.resume.Pending[i].Checksum {
+			return false, p.changed()
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// finish checks that the input had all the lines recorded in the state
+// file. If all rows have been processed, it removes the state file, so
+// that the next upload starts from the beginning.
+func (p *uploadProgress) finish(lines int) error {
+	if p.resume != nil && lines < p.resume.Line {
+		return p.changed()
+	}
+	if len(p.state.Pending) > 0 {
+		return nil
+	}
+	if err := os.Remove(p.filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// changed returns the error for an input that has changed since the
+// state file was written.
+func (p *uploadProgress) changed() error {
+	return fmt.Errorf("input has changed since state file %s was written for line %d (SPN %q); remove it to upload from the beginning", p.filename, p.resume.Line, p.resume.SPN)
+}
+
+// advance records the rows of the uploaded batch as processed and writes
+// the state file. Rows that failed with a transient error, e.g. because
+// the network was down, are recorded as pending instead, so that only
+// they are uploaded again when resuming. Rows of the same batch that
+// succeeded are not sent again.
+func (p *uploadProgress) advance(batch uploadBatch) error {
+	var changed bool
+	for _, item := range batch {
+		line := item.row.Line
+		if i := p.state.pending(line); i >= 0 {
+			p.state.Pending = append(p.state.Pending[:i], p.state.Pending[i+1:]...)
+			changed = true
+		}
+		if item.err != nil && retryable(item.err) {
+			p.state.Pending = append(p.state.Pending, pendingLine{Line: line, Checksum: item.checksum})
+			changed = true
+		}
+		if line > p.state.Line {
+			p.state.Line = line
+			p.state.SPN = item.row.SPN
+			p.state.Mode = item.row.Mode
+			p.state.Checksum = item.checksum
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	sort.Slice(p.state.Pending, func(i, j int) bool { return p.state.Pending[i].Line < p.state.Pending[j].Line })
+	p.state.Updated = time.Now()
+	return p.state.save(p.filename)
+}
+
+// retryable reports whether uploading a row that failed with err might
+// succeed when tried again later.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return meplatoapi.IsTransient(err)
+}
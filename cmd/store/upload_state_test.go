@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+const resumableUpload = `MODE;SPN;NAME;PRICE;ORDER_UNIT
+C;1000;"Product 1000";19.50;PCE
+C;2000;"Product 2000";abc;PCE
+C;3000;"Product 3000";0.50;PCE
+C;4000;"Product 4000";1.00;PCE
+`
+
+// getFlakyProductsService returns a products service backed by a test
+// server that responds with 503 to requests for the SPNs in down, which
+// can be changed while the server is running. It records the SPNs of all
+// requests in spns.
+func getFlakyProductsService(t *testing.T, down map[string]bool, spns *[]string) (*products.Service, *httptest.Server) {
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p struct{ Spn string }
+		json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		*spns = append(*spns, p.Spn)
+		unavailable := down[p.Spn]
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"code":503,"message":"Service Unavailable"}}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+	}))
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+	return service, ts
+}
+
+func TestUploadResume(t *testing.T) {
+	down := map[string]bool{"3000": true}
+	var spns []string
+	service, ts := getFlakyProductsService(t, down, &spns)
+	defer ts.Close()
+
+	statefile := filepath.Join(t.TempDir(), "upload.state")
+	cmd := &uploadCommand{statefile: statefile}
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(resumableUpload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 2 || len(res.Failed) != 2 {
+		t.Fatalf("expected 2 created and 2 failed rows; got: %d and %v", res.Created, res.Failed)
+	}
+
+	// The invalid row on line 3 is processed, the transient failure on
+	// line 4 is pending
+	state, err := loadUploadState(statefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil {
+		t.Fatal("expected state file to be written")
+	}
+	if state.PIN != "AD8CCDD5F9" || state.Line != 5 || state.SPN != "4000" || state.Mode != "C" || state.Checksum == "" {
+		t.Errorf("expected state for line %d; got: %+v", 5, state)
+	}
+	if len(state.Pending) != 1 || state.Pending[0].Line != 4 || state.Pending[0].Checksum == "" {
+		t.Errorf("expected line %d to be pending; got: %+v", 4, state.Pending)
+	}
+
+	// Resume after the service is back
+	delete(down, "3000")
+	spns = nil
+	res, err = cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(resumableUpload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Skipped != 3 || res.Created != 1 || len(res.Failed) != 0 {
+		t.Errorf("expected 3 skipped and 1 created rows; got: %d, %d, and %v", res.Skipped, res.Created, res.Failed)
+	}
+	if got := strings.Join(spns, ","); got != "3000" {
+		t.Errorf("expected requests for SPN 3000; got: %s", got)
+	}
+	if _, err := os.Stat(statefile); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed after completion; got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	res.PrintSummary(&buf)
+	if !strings.Contains(buf.String(), "Skipped: 3") {
+		t.Errorf("expected summary to report skipped rows; got:\n%s", buf.String())
+	}
+}
+
+func TestUploadResumeBatch(t *testing.T) {
+	// Lines 2 to 5 are uploaded in one batch, the row on line 3 fails
+	down := map[string]bool{"2000": true}
+	var spns []string
+	service, ts := getFlakyProductsService(t, down, &spns)
+	defer ts.Close()
+
+	input := strings.Replace(resumableUpload, "abc", "9.99", 1)
+	statefile := filepath.Join(t.TempDir(), "upload.state")
+	cmd := &uploadCommand{statefile: statefile, batchSize: 4}
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 3 || len(res.Failed) != 1 || res.Failed[0].SPN != "2000" {
+		t.Fatalf("expected 3 created rows and a failure for SPN 2000; got: %d and %v", res.Created, res.Failed)
+	}
+
+	// A second failure of the pending row keeps it pending
+	spns = nil
+	res, err = cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Skipped != 3 || len(res.Failed) != 1 {
+		t.Fatalf("expected 3 skipped rows and 1 failure; got: %d and %v", res.Skipped, res.Failed)
+	}
+	state, err := loadUploadState(statefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || state.Line != 5 || len(state.Pending) != 1 || state.Pending[0].Line != 3 {
+		t.Fatalf("expected line 3 to be pending after line 5; got: %+v", state)
+	}
+
+	// Only the failed row is sent again when resuming
+	delete(down, "2000")
+	spns = nil
+	res, err = cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Skipped != 3 || res.Created != 1 || len(res.Failed) != 0 {
+		t.Errorf("expected 3 skipped and 1 created rows; got: %d, %d, and %v", res.Skipped, res.Created, res.Failed)
+	}
+	if got := strings.Join(spns, ","); got != "2000" {
+		t.Errorf("expected requests for SPN 2000 only; got: %s", got)
+	}
+	if _, err := os.Stat(statefile); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed after completion; got: %v", err)
+	}
+}
+
+func TestUploadResumeChangedInput(t *testing.T) {
+	down := map[string]bool{"3000": true}
+	var spns []string
+	service, ts := getFlakyProductsService(t, down, &spns)
+	defer ts.Close()
+
+	statefile := filepath.Join(t.TempDir(), "upload.state")
+	cmd := &uploadCommand{statefile: statefile}
+	if _, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(resumableUpload)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []string{
+		strings.Replace(resumableUpload, "19.50", "19.99", 1),
+		strings.Replace(resumableUpload, "C;2000", "U;2000", 1),
+		"MODE;SPN;NAME;PRICE;ORDER_UNIT\nC;1000;\"Product 1000\";19.50;PCE\n",
+	}
+	for i, in := range tests {
+		spns = nil
+		_, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(in))
+		if err == nil || !strings.Contains(err.Error(), "input has changed") {
+			t.Errorf("%d. expected error for changed input; got: %v", i, err)
+		}
+		if len(spns) != 0 {
+			t.Errorf("%d. expected no requests; got: %v", i, spns)
+		}
+	}
+
+	if _, err := cmd.upload(context.Background(), service, "5094310527", strings.NewReader(resumableUpload)); err == nil {
+		t.Error("expected error for state file of another catalog; got: nil")
+	}
+}
@@ -0,0 +1,719 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+// getProductsService returns a products service backed by a test server
+// that accepts every request, except for products with SPN "missing".
+func getProductsService(t *testing.T) (*products.Service, *httptest.Server) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":404,"message":"Product not found"}}`)
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/products"):
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+		default:
+			fmt.Fprint(w, `{"kind":"store#productsUpdateResponse"}`)
+		}
+	}))
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+	return service, ts
+}
+
+func TestOpenInputGzip(t *testing.T) {
+	const content = "MODE;SPN\nD;1000\n"
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "catalog.csv")
+	if err := ioutil.WriteFile(plain, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	compressed := filepath.Join(dir, "catalog.csv.gz")
+	f, err := os.Create(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		gz   bool
+	}{
+		{plain, false},
+		{compressed, false},
+		{compressed, true},
+	} {
+		in, err := openInput(tt.name, tt.gz)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		data, err := ioutil.ReadAll(in)
+		in.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s: expected %q; got: %q", tt.name, content, string(data))
+		}
+	}
+
+	if _, err := openInput(plain, true); err == nil {
+		t.Error("expected error reading uncompressed input with gzip; got: nil")
+	}
+}
+
+func TestUploadResult(t *testing.T) {
+	service, ts := getProductsService(t)
+	defer ts.Close()
+
+	in := strings.NewReader(`MODE;SPN;NAME;PRICE;ORDER_UNIT
+C;1000;"Product 1000";19.50;PCE
+C;2000;"Product 2000";0.50;PCE
+U;2000;;0.49;EA
+D;1000;;;
+D;missing;;;
+C;3000;"Product 3000";abc;PCE
+X;4000;;;
+`)
+	cmd := new(uploadCommand)
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 2 || res.Updated != 1 || res.Deleted != 1 {
+		t.Errorf("expected 2 created, 1 updated, 1 deleted; got: %d, %d, %d", res.Created, res.Updated, res.Deleted)
+	}
+	if len(res.Failed) != 3 {
+		t.Fatalf("expected %d failed rows; got: %v", 3, res.Failed)
+	}
+	for i, want := range []uploadFailure{{Line: 6, SPN: "missing"}, {Line: 7, SPN: "3000"}, {Line: 8, SPN: "4000"}} {
+		if got := res.Failed[i]; got.Line != want.Line || got.SPN != want.SPN || got.Reason == "" {
+			t.Errorf("expected failure on line %d for SPN %q; got: %+v", want.Line, want.SPN, got)
+		}
+	}
+	if n := res.Rows(); n != 7 {
+		t.Errorf("expected %d rows; got: %d", 7, n)
+	}
+
+	var buf bytes.Buffer
+	res.PrintSummary(&buf)
+	if !strings.Contains(buf.String(), "Failed:  3") {
+		t.Errorf("expected summary to report failed rows; got:\n%s", buf.String())
+	}
+}
+
+func TestHandleKeepPriceAndPriceFormula(t *testing.T) {
+	for cell, want := range map[string]bool{"true": true, "YES": true, "1": true, "false": false, "No": false, "0": false} {
+		var r row
+		if err := handleKeepPrice(&r, cell); err != nil {
+			t.Fatalf("%q: %v", cell, err)
+		}
+		if r.KeepPrice == nil || *r.KeepPrice != want {
+			t.Errorf("%q: expected %v; got: %v", cell, want, r.KeepPrice)
+		}
+	}
+
+	var r row
+	if err := handleKeepPrice(&r, ""); err != nil || r.KeepPrice != nil {
+		t.Errorf("expected empty cell to leave KeepPrice unset; got: %v (%v)", r.KeepPrice, err)
+	}
+	if err := handleKeepPrice(&r, "maybe"); err == nil {
+		t.Error("expected error for invalid boolean; got: nil")
+	}
+	if err := handlePriceFormula(&r, ""); err != nil || r.PriceFormula != nil {
+		t.Errorf("expected empty cell to leave PriceFormula unset; got: %v (%v)", r.PriceFormula, err)
+	}
+	if err := handlePriceFormula(&r, "listPrice * 0.9"); err != nil || r.PriceFormula == nil || *r.PriceFormula != "listPrice * 0.9" {
+		t.Errorf("expected PriceFormula to be set; got: %v (%v)", r.PriceFormula, err)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		cell         string
+		decimalComma bool
+		want         float64
+		wantErr      bool
+	}{
+		{"19.50", false, 19.50, false},
+		{"1234.56", false, 1234.56, false},
+		{"19,50", false, 0, true},
+		{"19,50", true, 19.50, false},
+		{"1.234,56", true, 1234.56, false},
+		{"1.234.567,8", true, 1234567.8, false},
+		{"1.234", true, 1234, false},
+		{"42", true, 42, false},
+		{" 0,49 ", true, 0.49, false},
+		{"19.50", true, 0, true},
+		{"1.23,4", true, 0, true},
+		{"abc", true, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDecimal(tt.cell, tt.decimalComma)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q (decimal comma %v): expected error; got: %v", tt.cell, tt.decimalComma, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q (decimal comma %v): %v", tt.cell, tt.decimalComma, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q (decimal comma %v): expected %v; got: %v", tt.cell, tt.decimalComma, tt.want, got)
+		}
+	}
+}
+
+func TestHandlePriceDecimalComma(t *testing.T) {
+	r := row{DecimalComma: true}
+	if err := handlePrice(&r, "1.234,56"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Price == nil || *r.Price != 1234.56 {
+		t.Fatalf("expected price %v; got: %v", 1234.56, r.Price)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	handlers, ignored, err := parseHeader([]string{"MODE", "SPN", "PRICE"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handlers) != 3 {
+		t.Fatalf("expected %d handlers; got: %d", 3, len(handlers))
+	}
+	if len(ignored) != 0 {
+		t.Fatalf("expected no ignored columns; got: %v", ignored)
+	}
+
+	_, _, err = parseHeader([]string{"MODE", "SPN", "PRICE", "NAME", "PRICE"}, false)
+	if err == nil {
+		t.Fatal("expected error for duplicate column; got: nil")
+	}
+	if !strings.Contains(err.Error(), `"PRICE"`) {
+		t.Errorf("expected error to name the duplicate column; got: %v", err)
+	}
+
+	if _, _, err := parseHeader([]string{"MODE", "SPN", "COLOR"}, false); err == nil {
+		t.Fatal("expected error for unknown column; got: nil")
+	}
+	if _, _, err := parseHeader(nil, false); err == nil {
+		t.Fatal("expected error for empty header; got: nil")
+	}
+}
+
+func TestParseHeaderIgnoreUnknown(t *testing.T) {
+	handlers, ignored, err := parseHeader([]string{"MODE", "COLOR", "SPN", "NOTES"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handlers) != 4 {
+		t.Fatalf("expected %d handlers; got: %d", 4, len(handlers))
+	}
+	if len(ignored) != 2 || ignored[0] != "COLOR" || ignored[1] != "NOTES" {
+		t.Fatalf("expected ignored columns [COLOR NOTES]; got: %v", ignored)
+	}
+
+	r := new(row)
+	if err := parseRow(r, []string{"D", "red", "1000", "internal"}, handlers); err != nil {
+		t.Fatal(err)
+	}
+	if r.Mode != "D" || r.SPN != "1000" {
+		t.Fatalf("expected mode %q and SPN %q; got: %q and %q", "D", "1000", r.Mode, r.SPN)
+	}
+
+	if _, _, err := parseHeader([]string{"MODE", "SPN", "SPN", "COLOR"}, true); err == nil {
+		t.Fatal("expected error for duplicate column; got: nil")
+	}
+}
+
+func TestUploadIgnoreUnknownColumns(t *testing.T) {
+	service, ts := getProductsService(t)
+	defer ts.Close()
+
+	input := `MODE;SPN;NOTES;NAME;PRICE;ORDER_UNIT
+C;1000;"check price";"Product 1000";19.50;PCE
+`
+	if _, err := new(uploadCommand).upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(input)); err == nil {
+		t.Fatal("expected error for unknown column; got: nil")
+	}
+
+	cmd := &uploadCommand{ignoreUnknownColumns: true}
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 1 || len(res.Failed) != 0 {
+		t.Fatalf("expected 1 created and no failed rows; got: %d and %v", res.Created, res.Failed)
+	}
+	if len(res.Ignored) != 1 || res.Ignored[0] != "NOTES" {
+		t.Fatalf("expected ignored columns [NOTES]; got: %v", res.Ignored)
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	for cell, want := range map[string]bool{
+		"true": true, "True": true, "1": true, "yes": true, "YES": true, "y": true, "Y": true,
+		"false": false, "FALSE": false, "0": false, "no": false, "No": false, "n": false, " N ": false,
+	} {
+		b, err := parseBool(cell)
+		if err != nil {
+			t.Errorf("%q: %v", cell, err)
+			continue
+		}
+		if b == nil || *b != want {
+			t.Errorf("%q: expected %v; got: %v", cell, want, b)
+		}
+	}
+	for _, cell := range []string{"", "  "} {
+		if b, err := parseBool(cell); err != nil || b != nil {
+			t.Errorf("%q: expected nil; got: %v (%v)", cell, b, err)
+		}
+	}
+	for _, cell := range []string{"maybe", "2", "t", "on"} {
+		if _, err := parseBool(cell); err == nil {
+			t.Errorf("%q: expected error; got: nil", cell)
+		}
+	}
+}
+
+func TestUploadInvalidBoolean(t *testing.T) {
+	service, ts := getProductsService(t)
+	defer ts.Close()
+
+	in := strings.NewReader(`MODE;SPN;PRICE;KEEP_PRICE
+U;1000;9.99;y
+U;2000;9.99;maybe
+U;3000;9.99;
+`)
+	res, err := new(uploadCommand).upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Updated != 2 {
+		t.Errorf("expected %d updated rows; got: %d", 2, res.Updated)
+	}
+	if len(res.Failed) != 1 {
+		t.Fatalf("expected %d failed row; got: %v", 1, res.Failed)
+	}
+	if got := res.Failed[0]; got.Line != 3 || got.SPN != "2000" || !strings.Contains(got.Reason, `"maybe" is not a boolean`) {
+		t.Fatalf("expected parse error on line %d for SPN %q; got: %+v", 3, "2000", got)
+	}
+
+	var buf bytes.Buffer
+	res.PrintSummary(&buf)
+	if !strings.Contains(buf.String(), `line 3: SPN "2000"`) {
+		t.Errorf("expected summary to report the line; got:\n%s", buf.String())
+	}
+}
+
+func TestUploadUnitConversion(t *testing.T) {
+	var created []*products.CreateProduct
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := new(products.CreateProduct)
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			t.Error(err)
+		}
+		created = append(created, p)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	in := strings.NewReader(`MODE;SPN;NAME;PRICE;ORDER_UNIT;CU;CU_PER_OU;CONV_NUM;CONV_DENOM
+C;1000;"Bottle";0.99;CS;BO;12;1;2.5
+C;2000;"Crate";9.99;PCE;;;;
+C;3000;"Case";19.99;CS;BO;twelve;;
+C;4000;"Pack";4.99;PK;;;x;
+`)
+	cmd := new(uploadCommand)
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 2 {
+		t.Fatalf("expected %d created; got: %d", 2, res.Created)
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected %d requests; got: %d", 2, len(created))
+	}
+	p := created[0]
+	if p.ContentUnit != "BO" {
+		t.Errorf("expected content unit %q; got: %q", "BO", p.ContentUnit)
+	}
+	if p.CuPerOu == nil || *p.CuPerOu != 12 {
+		t.Errorf("expected CuPerOu %v; got: %v", 12, p.CuPerOu)
+	}
+	if p.ConversionNumerator == nil || *p.ConversionNumerator != 1 {
+		t.Errorf("expected ConversionNumerator %v; got: %v", 1, p.ConversionNumerator)
+	}
+	if p.ConversionDenumerator == nil || *p.ConversionDenumerator != 2.5 {
+		t.Errorf("expected ConversionDenumerator %v; got: %v", 2.5, p.ConversionDenumerator)
+	}
+	p = created[1]
+	if p.ContentUnit != "" || p.CuPerOu != nil || p.ConversionNumerator != nil || p.ConversionDenumerator != nil {
+		t.Errorf("expected empty cells to leave unit conversion unset; got: %+v", p)
+	}
+
+	if len(res.Failed) != 2 {
+		t.Fatalf("expected %d failed rows; got: %v", 2, res.Failed)
+	}
+	for i, want := range []uploadFailure{{Line: 4, SPN: "3000"}, {Line: 5, SPN: "4000"}} {
+		if got := res.Failed[i]; got.Line != want.Line || got.SPN != want.SPN || !strings.Contains(got.Reason, "not a number") {
+			t.Errorf("expected parse error on line %d for SPN %q; got: %+v", want.Line, want.SPN, got)
+		}
+	}
+}
+
+func TestUploadCountry(t *testing.T) {
+	var bodies []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+		} else {
+			fmt.Fprint(w, `{"kind":"store#productsUpdateResponse"}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	in := strings.NewReader(`MODE;SPN;NAME;PRICE;ORDER_UNIT;COUNTRY
+C;1000;"Product 1000";19.50;PCE;AT
+C;2000;"Product 2000";0.50;PCE;
+U;1000;;;;CH
+U;2000;;0.49;;
+`)
+	cmd := new(uploadCommand)
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Failed) > 0 {
+		t.Fatalf("expected no failures; got: %v", res.Failed)
+	}
+	if len(bodies) != 4 {
+		t.Fatalf("expected %d requests; got: %d", 4, len(bodies))
+	}
+	for i, want := range []string{"AT", "", "CH", ""} {
+		got, found := bodies[i]["country"]
+		if want == "" {
+			// Empty cells must not send a country, so that the server
+			// keeps defaulting to the country/region of the catalog.
+			if found {
+				t.Errorf("request %d: expected no country; got: %v", i+1, got)
+			}
+			continue
+		}
+		if got != want {
+			t.Errorf("request %d: expected country %q; got: %v", i+1, want, got)
+		}
+	}
+}
+
+func TestUploadClassifications(t *testing.T) {
+	var bodies []*products.UpdateProduct
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := new(products.UpdateProduct)
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, p)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+		} else {
+			fmt.Fprint(w, `{"kind":"store#productsUpdateResponse"}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	in := strings.NewReader(`MODE;SPN;NAME;PRICE;ORDER_UNIT;ECLASS_VERSION;ECLASS_CODE;UNSPSC_VERSION;UNSPSC_CODE
+C;1000;"Product 1000";19.50;PCE;5.1;19-01-01-01|19-01-01-02;16.0901;43211503|43211507
+U;1000;;;;5.1|7.0;19-01-01-01|19-01-01-02;;
+C;2000;"Product 2000";19.50;PCE;5.1|7.0|8.0;19-01-01-01|19-01-01-02;;
+`)
+	cmd := new(uploadCommand)
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("expected %d requests; got: %d", 2, len(bodies))
+	}
+
+	p := bodies[0]
+	if len(p.Eclasses) != 2 || p.Eclasses[0].Code != "19-01-01-01" || p.Eclasses[1].Code != "19-01-01-02" {
+		t.Fatalf("expected two eCl@ss codes; got: %+v", p.Eclasses)
+	}
+	for _, e := range p.Eclasses {
+		if e.Version != "5.1" {
+			t.Errorf("expected eCl@ss version %q for all codes; got: %q", "5.1", e.Version)
+		}
+	}
+	if len(p.Unspscs) != 2 || p.Unspscs[0].Code != "43211503" || p.Unspscs[1].Code != "43211507" || p.Unspscs[1].Version != "16.0901" {
+		t.Fatalf("expected two UNSPSC codes; got: %+v", p.Unspscs)
+	}
+
+	p = bodies[1]
+	if len(p.Eclasses) != 2 || p.Eclasses[0].Version != "5.1" || p.Eclasses[1].Version != "7.0" {
+		t.Fatalf("expected one version per eCl@ss code; got: %+v", p.Eclasses)
+	}
+	if p.Unspscs != nil {
+		t.Fatalf("expected no UNSPSC codes; got: %+v", p.Unspscs)
+	}
+
+	if len(res.Failed) != 1 || res.Failed[0].Line != 4 || !strings.Contains(res.Failed[0].Reason, "3 eCl@ss versions for 2 codes") {
+		t.Fatalf("expected mismatching versions to fail on line %d; got: %+v", 4, res.Failed)
+	}
+}
+
+func TestUploadBatch(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	var current, max int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		current++
+		if current > max {
+			max = current
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		current--
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+		default:
+			fmt.Fprint(w, `{"kind":"store#productsUpdateResponse"}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	in := strings.NewReader(`MODE;SPN;NAME;PRICE;ORDER_UNIT
+C;1000;"Product 1000";19.50;PCE
+C;2000;"Product 2000";0.50;PCE
+C;3000;"Product 3000";abc;PCE
+U;2000;;0.49;EA
+D;1000;;;
+C;4000;"Product 4000";1.00;PCE
+`)
+	cmd := &uploadCommand{batchSize: 10}
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 3 || res.Updated != 1 || res.Deleted != 1 {
+		t.Errorf("expected 3 created, 1 updated, 1 deleted; got: %d, %d, %d", res.Created, res.Updated, res.Deleted)
+	}
+	if len(res.Failed) != 1 || res.Failed[0].Line != 4 || res.Failed[0].SPN != "3000" {
+		t.Fatalf("expected failure on line %d; got: %+v", 4, res.Failed)
+	}
+	if max < 2 {
+		t.Errorf("expected rows to be uploaded concurrently; got: %d at most", max)
+	}
+
+	// Rows with the same SPN are uploaded in the order of the file
+	index := func(req string) int {
+		for i, r := range requests {
+			if r == req {
+				return i
+			}
+		}
+		t.Fatalf("expected request %q; got: %v", req, requests)
+		return -1
+	}
+	if index("POST /catalogs/AD8CCDD5F9/work/products") < 0 {
+		t.Fatal("expected create requests")
+	}
+	if index("POST /catalogs/AD8CCDD5F9/work/products/2000") < index("POST /catalogs/AD8CCDD5F9/work/products") {
+		t.Error("expected update of 2000 after its creation")
+	}
+}
+
+func TestUploadStripHTML(t *testing.T) {
+	var bodies []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+		} else {
+			fmt.Fprint(w, `{"kind":"store#productsUpdateResponse"}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	csv := `MODE;SPN;NAME;PRICE;ORDER_UNIT;DESCRIPTION
+C;1000;"Product 1000";19.50;PCE;"<p>Fish &amp; <b>Chips</b></p>"
+U;1000;;;;"<ul><li>One</li><li>Two</li></ul>"
+U;1000;;0.49;;
+`
+	for _, strip := range []bool{false, true} {
+		bodies = nil
+		cmd := &uploadCommand{stripHTML: strip}
+		res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", strings.NewReader(csv))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Failed) > 0 {
+			t.Fatalf("expected no failures; got: %v", res.Failed)
+		}
+		want := []string{"<p>Fish &amp; <b>Chips</b></p>", "<ul><li>One</li><li>Two</li></ul>", ""}
+		if strip {
+			want = []string{"Fish & Chips", "One\nTwo", ""}
+		}
+		if len(bodies) != len(want) {
+			t.Fatalf("expected %d requests; got: %d", len(want), len(bodies))
+		}
+		for i := range want {
+			got, found := bodies[i]["description"]
+			if want[i] == "" {
+				if found {
+					t.Errorf("strip=%v: request %d: expected no description; got: %v", strip, i+1, got)
+				}
+				continue
+			}
+			if got != want[i] {
+				t.Errorf("strip=%v: request %d: expected description %q; got: %v", strip, i+1, want[i], got)
+			}
+		}
+	}
+}
+
+func TestUploadIncomplete(t *testing.T) {
+	var bodies []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"kind":"store#productsCreateResponse"}`)
+		} else {
+			fmt.Fprint(w, `{"kind":"store#productsUpdateResponse"}`)
+		}
+	}))
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	in := strings.NewReader(`MODE;SPN;NAME;PRICE;ORDER_UNIT;INCOMPLETE
+C;1000;"Product 1000";19.50;PCE;yes
+C;2000;"Product 2000";0.50;PCE;
+U;1000;;;;false
+U;2000;;0.49;;
+U;3000;;0.49;;maybe
+`)
+	cmd := new(uploadCommand)
+	res, err := cmd.upload(context.Background(), service, "AD8CCDD5F9", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Failed) != 1 || res.Failed[0].Line != 6 {
+		t.Fatalf("expected line 6 to fail; got: %v", res.Failed)
+	}
+	if len(bodies) != 4 {
+		t.Fatalf("expected %d requests; got: %d", 4, len(bodies))
+	}
+	for i, want := range []interface{}{true, nil, false, nil} {
+		got, found := bodies[i]["incomplete"]
+		if want == nil {
+			// Empty cells must leave the flag unset instead of sending false
+			if found {
+				t.Errorf("request %d: expected no incomplete flag; got: %v", i+1, got)
+			}
+			continue
+		}
+		if got != want {
+			t.Errorf("request %d: expected incomplete %v; got: %v", i+1, want, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+// validateCommand validates the products of a catalog locally.
+type validateCommand struct {
+	area    string
+	outfile string
+	format  string
+}
+
+func init() {
+	RegisterCommand("validate", func(flags *flag.FlagSet) Command {
+		cmd := new(validateCommand)
+		flags.StringVar(&cmd.area, "area", "work", "Area to validate (work/live)")
+		flags.StringVar(&cmd.outfile, "o", "", "Report file")
+		flags.StringVar(&cmd.format, "format", "", "Report format (csv/json); defaults to json for .json report files and csv otherwise")
+		return cmd
+	})
+}
+
+func (c *validateCommand) Describe() string {
+	return "Validate the products of a catalog."
+}
+
+func (c *validateCommand) Usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s validate [-area work] [-o report.csv] [-format csv|json] <pin>\n", os.Args[0])
+	fmt.Fprint(os.Stderr, `
+Validate scrolls through the products of the catalog and checks them
+locally, e.g. for missing names and order units, negative prices, and
+malformed GTINs, currency codes, and eCl@ss and UNSPSC codes.
+
+It writes a report with one line per problem, listing the SPN of the
+product, the field, and the issue. The report is written in CSV format
+with a semicolon as a separator, or in JSON format with -format json or
+if the report file ends with .json. Without -o, the report is written to
+stdout.
+
+Validate exits with a non-zero exit code if any product is invalid.
+
+`)
+}
+
+func (c *validateCommand) Examples() []string {
+	return []string{
+		"ABCDE12345",
+		"-o report.csv ABCDE12345",
+		"-area live -o report.json ABCDE12345",
+	}
+}
+
+func (c *validateCommand) Run(args []string) error {
+	if len(args) != 1 {
+		return errors.New("no pin specified")
+	}
+	if _, err := c.reportFormat(); err != nil {
+		return err
+	}
+
+	service, err := GetProductsService()
+	if err != nil {
+		return err
+	}
+
+	// Print the summary to stderr if the report goes to stdout
+	out, summary := os.Stdout, os.Stderr
+	if c.outfile != "" {
+		f, err := os.OpenFile(c.outfile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out, summary = f, os.Stdout
+	}
+
+	report, err := c.validate(context.Background(), service, args[0], out)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(summary, "Checked %d products, %d invalid\n", report.Products, len(report.Invalid))
+	if !report.Valid() {
+		return fmt.Errorf("%d of %d products are invalid", len(report.Invalid), report.Products)
+	}
+	return nil
+}
+
+// reportFormat returns the format of the report, i.e. csv or json.
+func (c *validateCommand) reportFormat() (string, error) {
+	switch format := strings.ToLower(c.format); format {
+	case "csv", "json":
+		return format, nil
+	case "":
+		if strings.EqualFold(filepath.Ext(c.outfile), ".json") {
+			return "json", nil
+		}
+		return "csv", nil
+	default:
+		return "", fmt.Errorf("invalid report format %q; use csv or json", c.format)
+	}
+}
+
+// validate validates the products of the catalog with the given PIN and
+// writes the report to out.
+func (c *validateCommand) validate(ctx context.Context, service *products.Service, pin string, out io.Writer) (*products.CatalogReport, error) {
+	format, err := c.reportFormat()
+	if err != nil {
+		return nil, err
+	}
+	report, err := products.ValidateCatalog(ctx, service, pin, c.area)
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		err = report.WriteJSON(out)
+	} else {
+		err = report.WriteCSV(out)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestValidate(t *testing.T) {
+	var paths []string
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		paths = append(paths, r.URL.Path)
+		return "../../products/testdata/products.scroll.invalid"
+	})
+	defer ts.Close()
+	service, err := products.New(http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	var buf bytes.Buffer
+	cmd := &validateCommand{area: "work"}
+	report, err := cmd.validate(context.Background(), service, "AD8CCDD5F9", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "/catalogs/AD8CCDD5F9/work/products/scroll" {
+		t.Fatalf("expected to scroll the work area; got: %v", paths)
+	}
+	if report.Valid() || len(report.Invalid) != 2 {
+		t.Fatalf("expected %d invalid products; got: %d", 2, len(report.Invalid))
+	}
+	if !strings.HasPrefix(buf.String(), "SPN;Field;Issue\r\nMBA13;gtin;") {
+		t.Errorf("expected CSV report; got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	cmd = &validateCommand{area: "work", outfile: "report.json"}
+	if _, err := cmd.validate(context.Background(), service, "AD8CCDD5F9", &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"spn": "MBA13"`) {
+		t.Errorf("expected JSON report; got:\n%s", buf.String())
+	}
+}
+
+func TestValidateReportFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		outfile string
+		want    string
+	}{
+		{"", "", "csv"},
+		{"", "report.csv", "csv"},
+		{"", "report.JSON", "json"},
+		{"json", "report.txt", "json"},
+		{"CSV", "report.json", "csv"},
+		{"xml", "", ""},
+	}
+	for _, tt := range tests {
+		cmd := &validateCommand{format: tt.format, outfile: tt.outfile}
+		got, err := cmd.reportFormat()
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q/%q: expected error; got: nil", tt.format, tt.outfile)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q/%q: expected %q; got: %q, %v", tt.format, tt.outfile, tt.want, got, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package store2
+
+import (
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// Error is the error returned by all services if the server responds
+// with a status code other than 2xx.
+type Error = meplatoapi.Error
+
+// ErrResponseTooLarge is returned by all services when reading a response
+// body that exceeds MaxResponseBytes. Use errors.Is to test for it.
+var ErrResponseTooLarge = meplatoapi.ErrResponseTooLarge
+
+// IsQuotaExceeded reports whether err is a 429 response from the server
+// because a hard quota, e.g. the daily number of requests, is exhausted.
+// Requests should not be retried before the quota resets.
+func IsQuotaExceeded(err error) bool {
+	return meplatoapi.IsQuotaExceeded(err)
+}
+
+// IsRateLimited reports whether err is a 429 response from the server
+// because of a short-term rate limit. Requests can be retried soon.
+func IsRateLimited(err error) bool {
+	return meplatoapi.IsRateLimited(err)
+}
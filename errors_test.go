@@ -0,0 +1,33 @@
+package store2_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+)
+
+func TestIsQuotaExceeded(t *testing.T) {
+	tests := []struct {
+		err         error
+		quota       bool
+		rateLimited bool
+	}{
+		{&store2.Error{Code: 429, Message: "Daily quota exceeded"}, true, false},
+		{&store2.Error{Code: 429, Message: "Too many requests", Details: []string{"Daily limit of 10000 requests reached"}}, true, false},
+		{fmt.Errorf("wrapped: %w", &store2.Error{Code: 429, Message: "Quota exceeded"}), true, false},
+		{&store2.Error{Code: 429, Message: "Too many requests"}, false, true},
+		{&store2.Error{Code: 403, Message: "Quota exceeded"}, false, false},
+		{errors.New("quota"), false, false},
+		{nil, false, false},
+	}
+	for i, tt := range tests {
+		if got := store2.IsQuotaExceeded(tt.err); got != tt.quota {
+			t.Errorf("#%d: expected IsQuotaExceeded %v; got: %v", i, tt.quota, got)
+		}
+		if got := store2.IsRateLimited(tt.err); got != tt.rateLimited {
+			t.Errorf("#%d: expected IsRateLimited %v; got: %v", i, tt.rateLimited, got)
+		}
+	}
+}
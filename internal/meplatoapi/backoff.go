@@ -0,0 +1,92 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// maxBackoff is the longest delay returned by Backoff if Max is not set,
+// so that the delay never overflows time.Duration.
+const maxBackoff = time.Duration(1 << 62)
+
+// Backoff computes exponentially growing delays between attempts, e.g.
+// for retrying a request or polling a job. The zero value starts with a
+// delay of 1 second, doubles it with every attempt, and has no practical
+// cap and no jitter. A Backoff must not be used concurrently.
+type Backoff struct {
+	// Initial is the delay before the first retry (default 1s).
+	Initial time.Duration
+	// Max caps the delay (default 0, i.e. no practical cap).
+	Max time.Duration
+	// Multiplier is the factor by which the delay grows with every
+	// attempt (default 2).
+	Multiplier float64
+	// Jitter randomizes each delay by up to the given fraction, e.g. 0.2
+	// for delays between 80% and 100% of the computed delay, so that
+	// concurrent clients do not retry in lockstep (default 0).
+	Jitter float64
+
+	attempt int
+}
+
+// Next returns the delay before the next attempt and advances the
+// backoff.
+func (b *Backoff) Next() time.Duration {
+	initial := b.Initial
+	if initial <= 0 {
+		initial = time.Second
+	}
+	multiplier := b.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	ceiling := b.Max
+	if ceiling <= 0 || ceiling > maxBackoff {
+		ceiling = maxBackoff
+	}
+	d := float64(initial)
+	for i := 0; i < b.attempt && d < float64(ceiling); i++ {
+		d *= multiplier
+	}
+	if d > float64(ceiling) {
+		d = float64(ceiling)
+	}
+	if b.Jitter > 0 {
+		jitter := b.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= d * jitter * rand.Float64()
+	}
+	b.attempt++
+	return time.Duration(d)
+}
+
+// Wait waits for the next delay, or until ctx is done, in which case it
+// returns the context error.
+func (b *Backoff) Wait(ctx context.Context) error {
+	return sleep(ctx, b.Next())
+}
+
+// Attempt returns the number of delays returned by Next so far.
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+// Reset starts the backoff over at the initial delay, e.g. after a
+// successful attempt.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
@@ -0,0 +1,87 @@
+package meplatoapi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second}
+	for i, want := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		if got := b.Next(); got != want {
+			t.Errorf("%d. expected %v; got: %v", i, want, got)
+		}
+	}
+	if b.Attempt() != 6 {
+		t.Errorf("expected %d attempts; got: %d", 6, b.Attempt())
+	}
+	b.Reset()
+	if got := b.Next(); got != 100*time.Millisecond {
+		t.Errorf("expected %v after reset; got: %v", 100*time.Millisecond, got)
+	}
+
+	var zero Backoff
+	if got := zero.Next(); got != time.Second {
+		t.Errorf("expected zero value to start at %v; got: %v", time.Second, got)
+	}
+	if got := zero.Next(); got != 2*time.Second {
+		t.Errorf("expected zero value to double; got: %v", got)
+	}
+
+	b = Backoff{Initial: time.Second, Multiplier: 3}
+	b.Next()
+	if got := b.Next(); got != 3*time.Second {
+		t.Errorf("expected multiplier to be used; got: %v", got)
+	}
+}
+
+func TestBackoffManyAttempts(t *testing.T) {
+	var b Backoff
+	var last time.Duration
+	for i := 0; i < 100; i++ {
+		got := b.Next()
+		if got <= 0 || got < last {
+			t.Fatalf("%d. expected delay to grow; got: %v after %v", i, got, last)
+		}
+		last = got
+	}
+
+	b = Backoff{Initial: time.Second, Max: time.Minute}
+	for i := 0; i < 100; i++ {
+		b.Next()
+	}
+	if got := b.Next(); got != time.Minute {
+		t.Errorf("expected %v; got: %v", time.Minute, got)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		b := Backoff{Initial: time.Second, Jitter: 0.2}
+		if got := b.Next(); got < 800*time.Millisecond || got > time.Second {
+			t.Fatalf("expected delay between %v and %v; got: %v", 800*time.Millisecond, time.Second, got)
+		}
+	}
+}
+
+func TestBackoffWait(t *testing.T) {
+	b := Backoff{Initial: time.Millisecond}
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b = Backoff{Initial: time.Hour}
+	if err := b.Wait(ctx); err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+}
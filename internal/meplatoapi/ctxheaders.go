@@ -0,0 +1,37 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// SetContextHeaders sets a header of req for every context key in headers
+// whose value is found in the context of req. headers maps context keys
+// to header names. Values are formatted with fmt.Sprint, and empty values
+// are skipped. Headers that are already set on req are not overwritten.
+func SetContextHeaders(req *http.Request, headers map[interface{}]string) {
+	ctx := req.Context()
+	for key, name := range headers {
+		if name == "" || req.Header.Get(name) != "" {
+			continue
+		}
+		v := ctx.Value(key)
+		if v == nil {
+			continue
+		}
+		if s := fmt.Sprint(v); s != "" {
+			req.Header.Set(name, s)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type ctxKey string
+
+func TestSetContextHeaders(t *testing.T) {
+	headers := map[interface{}]string{
+		ctxKey("tenant"):      "X-Tenant-ID",
+		ctxKey("correlation"): "X-Correlation-ID",
+		ctxKey("missing"):     "X-Missing",
+		ctxKey("empty"):       "X-Empty",
+		ctxKey("explicit"):    "X-Explicit",
+		ctxKey("attempt"):     "X-Attempt",
+	}
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, ctxKey("tenant"), "acme")
+	ctx = context.WithValue(ctx, ctxKey("correlation"), "abc-123")
+	ctx = context.WithValue(ctx, ctxKey("empty"), "")
+	ctx = context.WithValue(ctx, ctxKey("explicit"), "from-context")
+	ctx = context.WithValue(ctx, ctxKey("attempt"), 2)
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://localhost/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Explicit", "from-request")
+
+	SetContextHeaders(req, headers)
+
+	want := map[string]string{
+		"X-Tenant-ID":      "acme",
+		"X-Correlation-ID": "abc-123",
+		"X-Missing":        "",
+		"X-Empty":          "",
+		"X-Explicit":       "from-request",
+		"X-Attempt":        "2",
+	}
+	for name, value := range want {
+		if got := req.Header.Get(name); got != value {
+			t.Errorf("expected %s to be %q; got: %q", name, value, got)
+		}
+	}
+	if _, found := req.Header["X-Missing"]; found {
+		t.Error("expected X-Missing not to be set")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvDebug is the environment variable that enables debug logging in
+// services created with NewFromEnv.
+const EnvDebug = "STORE2_DEBUG"
+
+// Logger is used to log requests and responses in debug mode. It is
+// implemented by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// DebugFromEnv reports whether debug logging is enabled via EnvDebug.
+func DebugFromEnv() bool {
+	debug, _ := strconv.ParseBool(os.Getenv(EnvDebug))
+	return debug
+}
+
+// LogRequest logs the method, URL, headers, and the indented body of req.
+// The Authorization header is redacted. The body of req remains readable.
+func LogRequest(logger Logger, req *http.Request) error {
+	if logger == nil {
+		logger = log.Default()
+	}
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		if err := BufferBody(req); err != nil {
+			return err
+		}
+		rc, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		body, err = ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	logger.Printf("%s %s\n%s%s", req.Method, req.URL, formatHeader(req.Header), formatBody(body))
+	return nil
+}
+
+// LogResponse logs the status, headers, and the indented body of res.
+// The body of res remains readable.
+func LogResponse(logger Logger, res *http.Response) error {
+	if logger == nil {
+		logger = log.Default()
+	}
+	var body []byte
+	if res.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		res.Body = struct {
+			io.Reader
+			io.Closer
+		}{bytes.NewReader(body), res.Body}
+	}
+	logger.Printf("%s\n%s%s", res.Status, formatHeader(res.Header), formatBody(body))
+	return nil
+}
+
+// formatHeader returns the headers sorted by name, one per line, with the
+// Authorization header redacted.
+func formatHeader(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf strings.Builder
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = "REDACTED"
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", name, value)
+	}
+	return buf.String()
+}
+
+// formatBody indents body if it is JSON, and returns it unchanged
+// otherwise.
+func formatBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		return "\n" + string(body)
+	}
+	return "\n" + buf.String()
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DecodeWarning describes a value of a JSON response that could not be
+// decoded into its field, e.g. a string where a number is expected. The
+// field is left at its zero value.
+type DecodeWarning struct {
+	// Field is the path of the value in the response, e.g. items[3].price.
+	Field string
+	// Err is the error decoding the value.
+	Err error
+}
+
+func (w *DecodeWarning) Error() string {
+	return fmt.Sprintf("%s: %v", w.Field, w.Err)
+}
+
+// DecodeLenient decodes the JSON from r into v, which must be a pointer.
+// Unlike json.Decoder, it does not fail if a value cannot be decoded into
+// its field, e.g. because the type does not match. Instead, it leaves the
+// field at its zero value, decodes the rest of the response, and returns
+// a warning for every such field. It only returns an error if r cannot be
+// read or does not contain valid JSON.
+func DecodeLenient(r io.Reader, v interface{}) ([]*DecodeWarning, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, fmt.Errorf("meplatoapi: cannot decode into %T", v)
+	}
+	if !json.Valid(data) {
+		// Report the syntax error as json does
+		return nil, json.Unmarshal(data, v)
+	}
+	var warnings []*DecodeWarning
+	decodeLenient(data, rv.Elem(), "", &warnings)
+	return warnings, nil
+}
+
+// decodeLenient decodes the valid JSON value data into v. If that fails,
+// it decodes the elements of objects and arrays one by one, so that only
+// the values that cannot be decoded are reported in warnings.
+func decodeLenient(data []byte, v reflect.Value, path string, warnings *[]*DecodeWarning) {
+	err := json.Unmarshal(data, v.Addr().Interface())
+	if err == nil {
+		return
+	}
+	v.Set(reflect.Zero(v.Type()))
+
+	t := v.Type()
+	_, unmarshaler := v.Addr().Interface().(json.Unmarshaler)
+	switch {
+	case t == timeType:
+		var ts string
+		if json.Unmarshal(data, &ts) == nil {
+			if tm, terr := ParseTime(ts); terr == nil {
+				v.Set(reflect.ValueOf(tm))
+				return
+			}
+		}
+	case unmarshaler && t.Kind() != reflect.Struct:
+		// Types with custom decoding are decoded as a whole, except for
+		// structs that only use it to parse times, see UnmarshalTimes
+	case t.Kind() == reflect.Ptr:
+		n := len(*warnings)
+		elem := reflect.New(t.Elem())
+		decodeLenient(data, elem.Elem(), path, warnings)
+		if len(*warnings) == n+1 && (*warnings)[n].Field == path {
+			// Leave the pointer nil if the value itself is invalid
+			return
+		}
+		v.Set(elem)
+		return
+	case t.Kind() == reflect.Struct && isJSONObject(data):
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			break
+		}
+		for _, name := range sortedKeys(fields) {
+			if i := decodeField(t, name); i >= 0 {
+				decodeLenient(fields[name], v.Field(i), joinPath(path, name), warnings)
+			}
+		}
+		return
+	case t.Kind() == reflect.Slice && isJSONArray(data):
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			break
+		}
+		s := reflect.MakeSlice(t, len(elems), len(elems))
+		for i, elem := range elems {
+			decodeLenient(elem, s.Index(i), fmt.Sprintf("%s[%d]", path, i), warnings)
+		}
+		v.Set(s)
+		return
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isJSONObject(data):
+		var entries map[string]json.RawMessage
+		if json.Unmarshal(data, &entries) != nil {
+			break
+		}
+		m := reflect.MakeMapWithSize(t, len(entries))
+		for _, key := range sortedKeys(entries) {
+			elem := reflect.New(t.Elem()).Elem()
+			decodeLenient(entries[key], elem, joinPath(path, key), warnings)
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		}
+		v.Set(m)
+		return
+	}
+	*warnings = append(*warnings, &DecodeWarning{Field: path, Err: err})
+}
+
+// decodeField returns the index of the field of the struct type t that
+// the JSON object key name is decoded into. Like encoding/json, it prefers
+// an exact match of the JSON name and falls back to a case-insensitive
+// one. It returns -1 if there is no such field.
+func decodeField(t reflect.Type, name string) int {
+	if f, found := fieldByJSONName(t, name); found && f.PkgPath == "" && len(f.Index) == 1 {
+		return f.Index[0]
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" || f.PkgPath != "" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// joinPath appends the object key name to path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// sortedKeys returns the keys of m in sorted order, so that warnings are
+// reported in a stable order.
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isJSONObject(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+func isJSONArray(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("["))
+}
@@ -0,0 +1,86 @@
+package meplatoapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeLenient(t *testing.T) {
+	type item struct {
+		Spn     string     `json:"spn"`
+		Price   float64    `json:"price"`
+		Created *time.Time `json:"created,omitempty"`
+	}
+	type response struct {
+		Items      []*item           `json:"items"`
+		Labels     map[string]int    `json:"labels"`
+		TotalItems int64             `json:"totalItems"`
+		Extra      map[string]string `json:"-"`
+	}
+
+	const data = `{
+		"items": [
+			{"spn": "1000", "price": 1.5},
+			{"spn": "2000", "price": "2,50", "created": "yesterday"},
+			{"SPN": "3000", "price": 3}
+		],
+		"labels": {"a": 1, "b": "two"},
+		"totalItems": 3,
+		"unknown": true
+	}`
+	var res response
+	warnings, err := DecodeLenient(strings.NewReader(data), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 3 {
+		t.Fatalf("expected %d items; got: %d", 3, len(res.Items))
+	}
+	if res.Items[0].Price != 1.5 || res.Items[1].Spn != "2000" || res.Items[1].Price != 0 || res.Items[2].Spn != "3000" {
+		t.Errorf("expected partially decoded items; got: %+v, %+v, %+v", res.Items[0], res.Items[1], res.Items[2])
+	}
+	if res.Items[1].Created != nil {
+		t.Errorf("expected invalid time to be left unset; got: %v", res.Items[1].Created)
+	}
+	if res.Labels["a"] != 1 || res.TotalItems != 3 {
+		t.Errorf("expected labels and total items to be decoded; got: %v and %d", res.Labels, res.TotalItems)
+	}
+
+	var fields []string
+	for _, w := range warnings {
+		fields = append(fields, w.Field)
+		if w.Err == nil || !strings.HasPrefix(w.Error(), w.Field+": ") {
+			t.Errorf("expected warning to describe the error; got: %q", w.Error())
+		}
+	}
+	if got, want := strings.Join(fields, ","), "items[1].created,items[1].price,labels.b"; got != want {
+		t.Errorf("expected warnings for %s; got: %s", want, got)
+	}
+}
+
+func TestDecodeLenientValid(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+	warnings, err := DecodeLenient(strings.NewReader(`{"name":"Produkt 1000"}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings; got: %v", warnings)
+	}
+	if v.Name != "Produkt 1000" {
+		t.Errorf("expected name %q; got: %q", "Produkt 1000", v.Name)
+	}
+}
+
+func TestDecodeLenientSyntaxError(t *testing.T) {
+	var v struct{}
+	if _, err := DecodeLenient(strings.NewReader(`{"name":`), &v); err == nil {
+		t.Fatal("expected error for invalid JSON; got: nil")
+	}
+	if _, err := DecodeLenient(strings.NewReader(`{}`), v); err == nil {
+		t.Fatal("expected error for non-pointer; got: nil")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ReadJSONIncludeEmpty works like ReadJSON, but makes sure that the
+// collections named by fields are transmitted even if they are empty or
+// nil, i.e. as [] for slices and {} for maps. Fields are identified by
+// their JSON names. v must be a struct or a pointer to a struct.
+func ReadJSONIncludeEmpty(v interface{}, fields []string) (io.Reader, error) {
+	if len(fields) == 0 {
+		return ReadJSON(v)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("meplatoapi: cannot include empty fields of %T", v)
+	}
+	empty := make(map[string]json.RawMessage)
+	for _, name := range fields {
+		f, found := fieldByJSONName(rv.Type(), name)
+		if !found {
+			return nil, fmt.Errorf("meplatoapi: unknown field %q in %T", name, v)
+		}
+		switch f.Type.Kind() {
+		case reflect.Slice, reflect.Array:
+			empty[name] = json.RawMessage("[]")
+		case reflect.Map:
+			empty[name] = json.RawMessage("{}")
+		default:
+			return nil, fmt.Errorf("meplatoapi: field %q in %T is not a collection", name, v)
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for name, value := range empty {
+		if _, found := m[name]; !found {
+			m[name] = value
+		}
+	}
+	return ReadJSON(m)
+}
+
+// fieldByJSONName returns the struct field of t that is serialized with
+// the given JSON name.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if tag == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Environment variables read by NewFromEnv in the service packages.
+const (
+	EnvURL      = "STORE2_URL"
+	EnvUser     = "STORE2_USER"
+	EnvPassword = "STORE2_PASSWORD"
+)
+
+// NewDefaultClient returns the HTTP client used when no client is passed
+// to a service explicitly.
+func NewDefaultClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+				DualStack: true,
+			}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+}
+
+// FromEnv returns the base URL, user, and password configured in the
+// environment. The base URL is empty if EnvURL is not set.
+func FromEnv() (baseURL, user, password string) {
+	return os.Getenv(EnvURL), os.Getenv(EnvUser), os.Getenv(EnvPassword)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// JobID returns the ID of the background job that a write operation has
+// triggered. It returns id, i.e. the jobId of the response body, if set.
+// Otherwise it extracts the ID from a Location header of res that points
+// to a job, e.g. https://store.meplato.com/api/v2/jobs/{id}. It returns
+// an empty string if the response refers to no job.
+func JobID(id string, res *http.Response) string {
+	if id != "" || res == nil {
+		return id
+	}
+	u, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == "jobs" && segments[i+1] != "" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
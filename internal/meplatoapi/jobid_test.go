@@ -0,0 +1,31 @@
+package meplatoapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJobID(t *testing.T) {
+	tests := []struct {
+		ID       string
+		Location string
+		Want     string
+	}{
+		{"", "", ""},
+		{"abc", "", "abc"},
+		{"abc", "https://store.meplato.com/api/v2/jobs/def", "abc"},
+		{"", "https://store.meplato.com/api/v2/jobs/def", "def"},
+		{"", "/api/v2/jobs/def/", "def"},
+		{"", "https://store.meplato.com/api/v2/jobs", ""},
+		{"", "https://store.meplato.com/api/v2/catalogs/PIN/work/products/1000", ""},
+	}
+	for i, tt := range tests {
+		res := &http.Response{Header: make(http.Header)}
+		if tt.Location != "" {
+			res.Header.Set("Location", tt.Location)
+		}
+		if got := JobID(tt.ID, res); got != tt.Want {
+			t.Errorf("%d. expected %q; got: %q", i, tt.Want, got)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrResponseTooLarge is returned when reading a response body that
+// exceeds the limit set with LimitBody. Use errors.Is to test for it.
+var ErrResponseTooLarge = errors.New("meplatoapi: response body too large")
+
+// responseTooLargeError reports the limit that has been exceeded.
+type responseTooLargeError struct {
+	limit int64
+}
+
+func (e *responseTooLargeError) Error() string {
+	return fmt.Sprintf("meplatoapi: response body exceeds the limit of %d bytes", e.limit)
+}
+
+func (e *responseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// LimitBody limits the body of res to n bytes. Reading more than n bytes
+// from the body returns an error that matches ErrResponseTooLarge instead
+// of silently truncating it. If the response announces a larger body via
+// Content-Length, LimitBody returns the error right away; the caller is
+// responsible for closing the body. If n is less than or equal to zero,
+// the body is not limited.
+func LimitBody(res *http.Response, n int64) error {
+	if n <= 0 || res == nil || res.Body == nil {
+		return nil
+	}
+	if res.ContentLength > n {
+		return &responseTooLargeError{limit: n}
+	}
+	res.Body = &limitedBody{ReadCloser: res.Body, limit: n, remaining: n}
+	return nil
+}
+
+// limitedBody returns an error once more than limit bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if b.remaining <= 0 {
+		// Make sure there is no more data before reporting EOF
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, &responseTooLargeError{limit: b.limit}
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
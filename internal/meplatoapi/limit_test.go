@@ -0,0 +1,71 @@
+package meplatoapi
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		Body          string
+		ContentLength int64
+		Limit         int64
+		Err           bool
+	}{
+		{"hello", -1, 0, false},
+		{"hello", -1, 5, false},
+		{"hello", -1, 10, false},
+		{"hello", -1, 4, true},
+		{"hello", 5, 4, true},
+	}
+	for i, tt := range tests {
+		res := &http.Response{
+			Body:          ioutil.NopCloser(strings.NewReader(tt.Body)),
+			ContentLength: tt.ContentLength,
+		}
+		err := LimitBody(res, tt.Limit)
+		if err == nil {
+			var data []byte
+			data, err = ioutil.ReadAll(res.Body)
+			if err == nil && string(data) != tt.Body {
+				t.Errorf("%d. expected body %q; got: %q", i, tt.Body, data)
+			}
+		}
+		if tt.Err {
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("%d. expected ErrResponseTooLarge; got: %v", i, err)
+			}
+		} else if err != nil {
+			t.Errorf("%d. expected no error; got: %v", i, err)
+		}
+	}
+}
+
+func TestCheckResponseTooLarge(t *testing.T) {
+	res := &http.Response{
+		StatusCode:    http.StatusInternalServerError,
+		Body:          ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 100))),
+		ContentLength: -1,
+	}
+	if err := LimitBody(res, 10); err != nil {
+		t.Fatal(err)
+	}
+	err := CheckResponse(res)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error; got: %T", err)
+	}
+	if e.Code != http.StatusInternalServerError {
+		t.Errorf("expected Code %d; got: %d", http.StatusInternalServerError, e.Code)
+	}
+	if !strings.Contains(e.Message, "exceeds") {
+		t.Errorf("expected Message to report the exceeded limit; got: %q", e.Message)
+	}
+	if _, err := io.Copy(ioutil.Discard, res.Body); err != nil {
+		t.Errorf("expected body to be restored; got: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Limiter limits the number of concurrent requests and keeps track of the
+// number of requests in flight. The zero value imposes no limit.
+//
+// The counter is an int32 because Limiter is a field of other structs,
+// where an int64 is not aligned for atomic access on 32-bit platforms.
+type Limiter struct {
+	mu       sync.Mutex
+	sem      chan struct{}
+	inFlight int32
+}
+
+// SetMax sets the maximum number of concurrent requests to n. If n is
+// less than or equal to zero, the number of requests is not limited.
+// Requests that are already in flight are not affected.
+func (l *Limiter) SetMax(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n > 0 {
+		l.sem = make(chan struct{}, n)
+	} else {
+		l.sem = nil
+	}
+}
+
+// InFlight returns the number of requests currently in flight.
+func (l *Limiter) InFlight() int {
+	return int(atomic.LoadInt32(&l.inFlight))
+}
+
+// Acquire blocks until a request may be sent or ctx is done. On success,
+// the caller must call release once the request has finished.
+func (l *Limiter) Acquire(ctx context.Context) (release func(), err error) {
+	l.mu.Lock()
+	sem := l.sem
+	l.mu.Unlock()
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	atomic.AddInt32(&l.inFlight, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt32(&l.inFlight, -1)
+			if sem != nil {
+				<-sem
+			}
+		})
+	}, nil
+}
+
+// Send sends req like the package-level Send, but waits for a free slot
+// first. The slot is held until the body of the response is closed.
+func (l *Limiter) Send(client *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	release, err := l.Acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	res, err := Send(client, req, retries)
+	if err != nil || res == nil || res.Body == nil {
+		release()
+		return res, err
+	}
+	res.Body = &releaseOnClose{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+// releaseOnClose calls release when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
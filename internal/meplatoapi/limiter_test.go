@@ -0,0 +1,37 @@
+package meplatoapi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiterAcquire(t *testing.T) {
+	var l Limiter
+	l.SetMax(1)
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := l.InFlight(); n != 1 {
+		t.Fatalf("expected %d in flight; got: %d", 1, n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v; got: %v", context.DeadlineExceeded, err)
+	}
+
+	release()
+	release()
+	if n := l.InFlight(); n != 0 {
+		t.Fatalf("expected %d in flight; got: %d", 0, n)
+	}
+	release, err = l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
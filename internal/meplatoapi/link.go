@@ -0,0 +1,109 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ParseLinkHeader parses the Link headers of res as described in RFC 5988
+// and returns the URLs by relation type, e.g. links["next"]. A link with
+// several relation types, e.g. rel="prev previous", is returned for each
+// of them. If a relation type appears more than once, the first link
+// wins. Malformed links are skipped.
+func ParseLinkHeader(res *http.Response) map[string]string {
+	links := make(map[string]string)
+	if res == nil {
+		return links
+	}
+	for _, header := range res.Header.Values("Link") {
+		for header != "" {
+			var url string
+			var params []string
+			url, params, header = nextLink(header)
+			if url == "" {
+				continue
+			}
+			for _, param := range params {
+				name, value, ok := strings.Cut(param, "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				value = strings.Trim(strings.TrimSpace(value), `"`)
+				for _, rel := range strings.Fields(strings.ToLower(value)) {
+					if _, found := links[rel]; !found {
+						links[rel] = url
+					}
+				}
+			}
+		}
+	}
+	return links
+}
+
+// PageLinks returns the URLs of the next and the previous page from the
+// Link headers of res. It returns empty strings if there are none.
+func PageLinks(res *http.Response) (next, prev string) {
+	links := ParseLinkHeader(res)
+	prev = links["prev"]
+	if prev == "" {
+		prev = links["previous"]
+	}
+	return links["next"], prev
+}
+
+// nextLink parses the first link of a Link header value, e.g.
+// `<https://example.com/?skip=20>; rel="next"`, and returns its URL, its
+// parameters, and the rest of the header. URL is empty if the link is
+// malformed.
+func nextLink(header string) (url string, params []string, rest string) {
+	header = strings.TrimLeft(header, " \t,")
+	if !strings.HasPrefix(header, "<") {
+		// Skip to the next link
+		if i := strings.Index(header, ","); i >= 0 {
+			return "", nil, header[i+1:]
+		}
+		return "", nil, ""
+	}
+	end := strings.Index(header, ">")
+	if end < 0 {
+		return "", nil, ""
+	}
+	url = strings.TrimSpace(header[1:end])
+	header = header[end+1:]
+
+	// Parameters are separated by semicolons and end at the next comma
+	// outside of a quoted string
+	var quoted bool
+	start := 0
+	for i := 0; i < len(header); i++ {
+		switch c := header[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == ';' && !quoted:
+			if p := strings.TrimSpace(header[start:i]); p != "" {
+				params = append(params, p)
+			}
+			start = i + 1
+		case c == ',' && !quoted:
+			if p := strings.TrimSpace(header[start:i]); p != "" {
+				params = append(params, p)
+			}
+			return url, params, header[i+1:]
+		}
+	}
+	if p := strings.TrimSpace(header[start:]); p != "" {
+		params = append(params, p)
+	}
+	return url, params, ""
+}
@@ -0,0 +1,61 @@
+package meplatoapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		Headers []string
+		Want    map[string]string
+	}{
+		{nil, map[string]string{}},
+		{
+			[]string{`<https://example.com/items?skip=20>; rel="next"`},
+			map[string]string{"next": "https://example.com/items?skip=20"},
+		},
+		{
+			[]string{`<https://example.com/items?skip=20>; rel="next", <https://example.com/items?skip=0>; title="a, b"; rel="prev previous"`},
+			map[string]string{
+				"next":     "https://example.com/items?skip=20",
+				"prev":     "https://example.com/items?skip=0",
+				"previous": "https://example.com/items?skip=0",
+			},
+		},
+		{
+			[]string{`<https://example.com/a>; rel=next`, `<https://example.com/b>; REL="Next Last"`},
+			map[string]string{"next": "https://example.com/a", "last": "https://example.com/b"},
+		},
+		{
+			[]string{`https://example.com/malformed; rel="next", <https://example.com/ok>; rel="first"`},
+			map[string]string{"first": "https://example.com/ok"},
+		},
+		{
+			[]string{`<https://example.com/unterminated; rel="next"`},
+			map[string]string{},
+		},
+	}
+	for i, tt := range tests {
+		res := &http.Response{Header: make(http.Header)}
+		for _, h := range tt.Headers {
+			res.Header.Add("Link", h)
+		}
+		if got := ParseLinkHeader(res); !reflect.DeepEqual(got, tt.Want) {
+			t.Errorf("%d. expected %v; got: %v", i, tt.Want, got)
+		}
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	res := &http.Response{Header: make(http.Header)}
+	res.Header.Set("Link", `<https://example.com/?skip=40>; rel="next", <https://example.com/?skip=0>; rel="previous"`)
+	next, prev := PageLinks(res)
+	if next != "https://example.com/?skip=40" {
+		t.Errorf("expected next %q; got: %q", "https://example.com/?skip=40", next)
+	}
+	if prev != "https://example.com/?skip=0" {
+		t.Errorf("expected prev %q; got: %q", "https://example.com/?skip=0", prev)
+	}
+}
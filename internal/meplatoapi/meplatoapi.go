@@ -15,6 +15,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -53,12 +54,23 @@ type errorReply struct {
 }
 
 // CheckResponse returns an error (of type *Error) if the response status
-// code is not 2xx.
+// code is not 2xx. The body of a successful response is left untouched.
+// The body of an error response is buffered into the error and restored
+// on res, so it can be read again, e.g. for logging.
 func CheckResponse(res *http.Response) error {
 	if res.StatusCode >= 200 && res.StatusCode <= 299 {
 		return nil
 	}
 	slurp, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(slurp))
+	if errors.Is(err, ErrResponseTooLarge) {
+		return &Error{
+			Code:    res.StatusCode,
+			Message: err.Error(),
+			Body:    string(slurp),
+		}
+	}
 	if err == nil {
 		jerr := new(errorReply)
 		err = json.Unmarshal(slurp, jerr)
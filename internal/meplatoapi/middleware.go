@@ -0,0 +1,33 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import "net/http"
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc, e.g. to log, measure, cache, or
+// rewrite requests and responses. It may return a response without
+// calling next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Chain wraps rt in mws. The first middleware is the outermost, i.e. it
+// sees the request first and the response last.
+func Chain(rt RoundTripFunc, mws []Middleware) RoundTripFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			rt = mws[i](rt)
+		}
+	}
+	return rt
+}
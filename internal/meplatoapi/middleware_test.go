@@ -0,0 +1,49 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				res, err := next(req)
+				calls = append(calls, name+" after")
+				return res, err
+			}
+		}
+	}
+	rt := func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "send")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	req, _ := http.NewRequest("GET", "https://store.meplato.com/api/v2/catalogs", nil)
+	res, err := Chain(rt, []Middleware{mw("a"), nil, mw("b")})(req)
+	if err != nil {
+		t.Fatalf("expected no error; got: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d; got: %d", http.StatusOK, res.StatusCode)
+	}
+	want := []string{"a before", "b before", "send", "b after", "a after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected calls %v; got: %v", want, calls)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// PageRef refers to a page of a listing paginated by skip and take, e.g.
+// to persist the position of a search and resume it later.
+type PageRef struct {
+	// Skip is the number of items to skip.
+	Skip int64 `json:"skip"`
+	// Take is the number of items to return; 0 means the server default.
+	Take int64 `json:"take,omitempty"`
+}
+
+// ParsePageRef parses the skip and take parameters of a link, e.g. the
+// NextLink of a search response. It returns nil if link is empty, i.e.
+// if there is no such page. Missing parameters are returned as zero.
+func ParsePageRef(link string) (*PageRef, error) {
+	if link == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("meplatoapi: invalid page link %q: %v", link, err)
+	}
+	q := u.Query()
+	ref := new(PageRef)
+	if ref.Skip, err = parsePageParam(q, "skip"); err != nil {
+		return nil, err
+	}
+	if ref.Take, err = parsePageParam(q, "take"); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+func parsePageParam(q url.Values, name string) (int64, error) {
+	v := q.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("meplatoapi: invalid %s %q in page link", name, v)
+	}
+	return n, nil
+}
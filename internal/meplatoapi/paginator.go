@@ -0,0 +1,83 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"context"
+)
+
+// Paginator iterates over the pages of a paginated endpoint, regardless of
+// whether the endpoint uses skip and take or page tokens.
+type Paginator[T any] struct {
+	next      func(ctx context.Context) (items []T, more bool, err error)
+	total     func() int64
+	done      bool
+	processed int64
+}
+
+// NewPaginator creates a Paginator that calls next to fetch a page. next
+// returns the items of the page and whether there are more pages.
+func NewPaginator[T any](next func(ctx context.Context) (items []T, more bool, err error)) *Paginator[T] {
+	return &Paginator[T]{next: next}
+}
+
+// Next returns the items of the next page. Pages without items are
+// skipped. ok is false if there are no more pages.
+func (p *Paginator[T]) Next(ctx context.Context) (items []T, ok bool, err error) {
+	for !p.done {
+		items, more, err := p.next(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		p.done = !more
+		if len(items) > 0 {
+			p.processed += int64(len(items))
+			return items, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// WithTotal sets the function that reports the total number of items,
+// e.g. as returned by the first page. It returns p.
+func (p *Paginator[T]) WithTotal(total func() int64) *Paginator[T] {
+	p.total = total
+	return p
+}
+
+// Processed returns the number of items returned by Next so far.
+func (p *Paginator[T]) Processed() int64 {
+	return p.processed
+}
+
+// Total returns the total number of items as reported by the server, or
+// 0 if it is unknown, e.g. before the first call to Next.
+func (p *Paginator[T]) Total() int64 {
+	if p.total == nil {
+		return 0
+	}
+	return p.total()
+}
+
+// SkipTake adapts an endpoint that is paginated by skip and take for use
+// with NewPaginator. fetch returns the items starting at skip and the
+// total number of items.
+func SkipTake[T any](skip int64, fetch func(ctx context.Context, skip int64) (items []T, total int64, err error)) func(ctx context.Context) ([]T, bool, error) {
+	return func(ctx context.Context) ([]T, bool, error) {
+		items, total, err := fetch(ctx, skip)
+		if err != nil {
+			return nil, false, err
+		}
+		skip += int64(len(items))
+		return items, len(items) > 0 && skip < total, nil
+	}
+}
@@ -0,0 +1,72 @@
+package meplatoapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPaginatorSkipTake(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7}
+	var skips []int64
+	p := NewPaginator(SkipTake(1, func(ctx context.Context, skip int64) ([]int, int64, error) {
+		skips = append(skips, skip)
+		end := skip + 3
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		return data[skip:end], int64(len(data)), nil
+	}))
+
+	var got []int
+	for {
+		items, ok, err := p.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, items...)
+	}
+	if len(got) != 6 || got[0] != 2 || got[5] != 7 {
+		t.Fatalf("expected items 2..7; got: %v", got)
+	}
+	if len(skips) != 2 || skips[0] != 1 || skips[1] != 4 {
+		t.Fatalf("expected skips [1 4]; got: %v", skips)
+	}
+	if _, ok, _ := p.Next(context.Background()); ok {
+		t.Fatal("expected no more pages")
+	}
+	if n := p.Processed(); n != 6 {
+		t.Fatalf("expected %d processed items; got: %d", 6, n)
+	}
+	if n := p.Total(); n != 0 {
+		t.Fatalf("expected unknown total without WithTotal; got: %d", n)
+	}
+}
+
+func TestPaginatorSkipsEmptyPagesAndReturnsErrors(t *testing.T) {
+	pages := [][]int{nil, {1, 2}, nil}
+	var calls int
+	p := NewPaginator(func(ctx context.Context) ([]int, bool, error) {
+		page := pages[calls]
+		calls++
+		return page, calls < len(pages), nil
+	})
+	items, ok, err := p.Next(context.Background())
+	if err != nil || !ok || len(items) != 2 {
+		t.Fatalf("expected second page; got: %v, %v, %v", items, ok, err)
+	}
+	if _, ok, err := p.Next(context.Background()); ok || err != nil {
+		t.Fatalf("expected no more pages; got: %v, %v", ok, err)
+	}
+
+	failure := errors.New("failure")
+	p = NewPaginator(func(ctx context.Context) ([]int, bool, error) {
+		return nil, false, failure
+	})
+	if _, _, err := p.Next(context.Background()); err != failure {
+		t.Fatalf("expected %v; got: %v", failure, err)
+	}
+}
@@ -0,0 +1,48 @@
+package meplatoapi
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	var calls int32
+	err := ForEach(context.Background(), 10, 3, func(ctx context.Context, i int) {
+		atomic.AddInt32(&calls, 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 10 {
+		t.Fatalf("expected %d calls; got: %d", 10, calls)
+	}
+}
+
+func TestForEachCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int32
+	err := ForEach(ctx, 10, 3, func(ctx context.Context, i int) {
+		atomic.AddInt32(&calls, 1)
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected %d calls; got: %d", 0, calls)
+	}
+}
+
+func TestForEachCanceledAfterLast(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := ForEach(ctx, 3, 1, func(ctx context.Context, i int) {
+		if i == 2 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("expected no error once every index has run; got: %v", err)
+	}
+}
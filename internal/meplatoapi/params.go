@@ -0,0 +1,29 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"fmt"
+)
+
+// RequireParams returns an error if one of the named path parameters is
+// missing or empty. Use it before Expand so that a request is not sent to
+// a malformed URL like /catalogs//products.
+func RequireParams(params map[string]interface{}, names ...string) error {
+	for _, name := range names {
+		v, found := params[name]
+		if !found || v == nil || fmt.Sprint(v) == "" {
+			return fmt.Errorf("meplatoapi: parameter %q must not be empty", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WithProxy returns a copy of client whose transport sends all requests
+// through proxy instead of the proxy configured in the environment. If
+// proxy is nil, requests are sent directly. The transport of client must
+// be nil or an *http.Transport; it is cloned, so client is unchanged. The
+// proxy URL must have the scheme http, https, or socks5 and a host.
+func WithProxy(client *http.Client, proxy *url.URL) (*http.Client, error) {
+	if proxy != nil {
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("meplatoapi: proxy %q must have the scheme http, https, or socks5", proxy.Redacted())
+		}
+		if proxy.Host == "" {
+			return nil, fmt.Errorf("meplatoapi: proxy %q has no host", proxy.Redacted())
+		}
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("meplatoapi: cannot set a proxy on a transport of type %T", t)
+	}
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	} else {
+		transport.Proxy = nil
+	}
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
@@ -0,0 +1,56 @@
+package meplatoapi
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestWithProxy(t *testing.T) {
+	client := NewDefaultClient()
+	proxy, _ := url.Parse("http://proxy.example.com:3128")
+	c, err := WithProxy(client, proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c == client || c.Transport == client.Transport {
+		t.Fatal("expected client and transport to be copied")
+	}
+	req, _ := http.NewRequest("GET", "https://store.meplato.com/api/v2", nil)
+	got, err := c.Transport.(*http.Transport).Proxy(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.String() != proxy.String() {
+		t.Fatalf("expected proxy %v; got: %v", proxy, got)
+	}
+
+	c, err = WithProxy(client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Transport.(*http.Transport).Proxy != nil {
+		t.Fatal("expected no proxy")
+	}
+
+	for _, s := range []string{"ftp://proxy.example.com", "http://", "proxy.example.com:3128"} {
+		u, err := url.Parse(s)
+		if err != nil {
+			continue
+		}
+		if _, err := WithProxy(client, u); err == nil {
+			t.Errorf("%q: expected error; got: nil", s)
+		}
+	}
+
+	custom := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) { return nil, nil })}
+	if _, err := WithProxy(custom, proxy); err == nil {
+		t.Error("expected error for custom transport; got: nil")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
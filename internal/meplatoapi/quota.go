@@ -0,0 +1,49 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// quotaKeywords are used to tell a hard quota apart from a short-term
+// rate limit in the message or details of a 429 response.
+var quotaKeywords = []string{"quota", "daily limit", "monthly limit"}
+
+// IsQuotaExceeded reports whether err is a 429 response from the server
+// because a hard quota, e.g. the daily number of requests, is exhausted.
+// Requests should not be retried before the quota resets.
+func IsQuotaExceeded(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) || e.Code != http.StatusTooManyRequests {
+		return false
+	}
+	texts := append([]string{e.Message}, e.Details...)
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, keyword := range quotaKeywords {
+			if strings.Contains(text, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsRateLimited reports whether err is a 429 response from the server
+// because of a short-term rate limit. Requests can be retried soon.
+func IsRateLimited(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusTooManyRequests && !IsQuotaExceeded(err)
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryDelay is the time to wait before the first retry. It doubles with
+// every subsequent attempt, up to MaxRetryDelay.
+var RetryDelay = 250 * time.Millisecond
+
+// MaxRetryDelay is the longest time to wait between two attempts.
+var MaxRetryDelay = 30 * time.Second
+
+// Send sends req with client. If ShouldRetry reports that the request is
+// worth another attempt, Send retries the request up to retries times.
+// If the response has a Retry-After header, Send waits as long as the
+// server asks for, or returns the response if that is longer than
+// MaxRetryDelay.
+// The request body is buffered before the first attempt so that it can be
+// sent again, e.g. for POST and PUT requests.
+func Send(client *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	if retries > 0 {
+		if err := BufferBody(req); err != nil {
+			return nil, err
+		}
+	}
+	ctx := req.Context()
+	backoff := Backoff{Initial: RetryDelay, Max: MaxRetryDelay}
+	attempts, _ := ctx.Value(attemptsKey{}).(*int)
+	for attempt := 0; ; attempt++ {
+		if attempts != nil {
+			*attempts = attempt + 1
+		}
+		res, err := client.Do(req)
+		if attempt >= retries || ctx.Err() != nil || !ShouldRetry(req, res, err) {
+			return res, err
+		}
+		delay := backoff.Next()
+		if d, ok := RetryAfter(res); ok {
+			if d > MaxRetryDelay {
+				return res, err
+			}
+			delay = d
+		} else if RetryDelay <= 0 {
+			delay = 0
+		}
+		drainBody(res)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		req = req.Clone(context.WithValue(ctx, attemptKey{}, attempt+1))
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// attemptKey is the context key for the number of the attempt of a request
+// sent by Send.
+type attemptKey struct{}
+
+// attemptsKey is the context key for the counter passed to CountAttempts.
+type attemptsKey struct{}
+
+// CountAttempts returns a shallow copy of req for which Send stores the
+// number of attempts in n, i.e. 1 if the request is not retried.
+func CountAttempts(req *http.Request, n *int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptsKey{}, n))
+}
+
+// Retried reports whether res is the response to a request that Send has
+// retried at least once, i.e. whether an earlier attempt of the request
+// might already have reached the server.
+func Retried(res *http.Response) bool {
+	if res == nil || res.Request == nil {
+		return false
+	}
+	attempt, _ := res.Request.Context().Value(attemptKey{}).(int)
+	return attempt > 0
+}
+
+// BufferBody reads the body of req into memory and sets req.GetBody so
+// that the body can be read again. Requests created with http.NewRequest
+// from a *bytes.Buffer, *bytes.Reader, or *strings.Reader already have
+// GetBody set and are left unchanged.
+func BufferBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(data))
+	return nil
+}
+
+// ShouldRetry reports whether a request req that returned res and err is
+// worth another attempt. It returns true for responses with a status code
+// that indicates a transient condition (429, 502, 503, or 504) and for
+// network errors like DNS failures, refused or reset connections, and
+// timeouts. Errors of the request context, i.e. context.Canceled and
+// context.DeadlineExceeded, are never retried as the caller wants to stop.
+//
+// Requests that are not idempotent, e.g. POST, are only retried if the
+// request has certainly not been processed by the server: after 429 and
+// 503 responses, and after network errors if the host could not be
+// resolved or connected to. A 502 or 504 response means that a gateway
+// gave up waiting, not that the request has failed.
+func ShouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if err == nil {
+		if res == nil {
+			return false
+		}
+		switch res.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		case http.StatusBadGateway, http.StatusGatewayTimeout:
+			return req == nil || isIdempotent(req)
+		}
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if req != nil && req.Context().Err() != nil {
+		return false
+	}
+
+	// Errors before the request has been sent
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	// Errors after the request may have reached the server
+	if !isIdempotent(req) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return opErr != nil
+}
+
+// IsTransient reports whether err, as returned by an operation, is worth
+// another attempt according to ShouldRetry, e.g. an *Error with status
+// code 503 or a network error. The operation is assumed to be idempotent,
+// so only use it to decide whether to repeat operations like upsert.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return ShouldRetry(nil, &http.Response{StatusCode: e.Code}, nil)
+	}
+	return ShouldRetry(&http.Request{Method: http.MethodPut}, nil, err)
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of
+// res, given either in seconds or as an HTTP date. It returns false if
+// res has no valid Retry-After header.
+func RetryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+	v := strings.TrimSpace(res.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		if secs > int64(math.MaxInt64/time.Second) {
+			return math.MaxInt64, true
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	d := time.Until(t)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// isIdempotent reports whether req can be sent again without changing
+// the result on the server.
+func isIdempotent(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// drainBody reads the rest of the response body and closes it, so that
+// the underlying connection can be reused.
+func drainBody(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,263 @@
+package meplatoapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestShouldRetry(t *testing.T) {
+	get, _ := http.NewRequest("GET", "https://store.meplato.com/api/v2/catalogs", nil)
+	post, _ := http.NewRequest("POST", "https://store.meplato.com/api/v2/catalogs", nil)
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	canceled := get.WithContext(canceledCtx)
+
+	wrap := func(op string, err error) error {
+		return &url.Error{Op: "Get", URL: get.URL.String(), Err: &net.OpError{Op: op, Net: "tcp", Err: err}}
+	}
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		res  *http.Response
+		err  error
+		want bool
+	}{
+		{"200", get, &http.Response{StatusCode: 200}, nil, false},
+		{"500", get, &http.Response{StatusCode: 500}, nil, false},
+		{"429", post, &http.Response{StatusCode: 429}, nil, true},
+		{"502", get, &http.Response{StatusCode: 502}, nil, true},
+		{"502 on POST", post, &http.Response{StatusCode: 502}, nil, false},
+		{"503", post, &http.Response{StatusCode: 503}, nil, true},
+		{"504", get, &http.Response{StatusCode: 504}, nil, true},
+		{"504 on POST", post, &http.Response{StatusCode: 504}, nil, false},
+		{"no response", get, nil, nil, false},
+
+		{"temporary DNS error", post, nil, wrap("dial", &net.DNSError{Err: "server misbehaving", Name: "store.meplato.com", IsTemporary: true}), true},
+		{"unknown host", get, nil, wrap("dial", &net.DNSError{Err: "no such host", Name: "store.meplato.com", IsNotFound: true}), false},
+		{"connection refused", post, nil, wrap("dial", os.NewSyscallError("connect", syscall.ECONNREFUSED)), true},
+		{"dial timeout", post, nil, wrap("dial", timeoutError{}), true},
+
+		{"connection reset on GET", get, nil, wrap("read", os.NewSyscallError("read", syscall.ECONNRESET)), true},
+		{"connection reset on POST", post, nil, wrap("read", os.NewSyscallError("read", syscall.ECONNRESET)), false},
+		{"EOF on GET", get, nil, &url.Error{Op: "Get", URL: get.URL.String(), Err: io.EOF}, true},
+		{"EOF on POST", post, nil, &url.Error{Op: "Post", URL: post.URL.String(), Err: io.EOF}, false},
+		{"unexpected EOF on GET", get, nil, io.ErrUnexpectedEOF, true},
+		{"read timeout on GET", get, nil, &url.Error{Op: "Get", URL: get.URL.String(), Err: timeoutError{}}, true},
+		{"read timeout on POST", post, nil, &url.Error{Op: "Post", URL: post.URL.String(), Err: timeoutError{}}, false},
+
+		{"context canceled", get, nil, &url.Error{Op: "Get", URL: get.URL.String(), Err: context.Canceled}, false},
+		{"context deadline", get, nil, &url.Error{Op: "Get", URL: get.URL.String(), Err: context.DeadlineExceeded}, false},
+		{"request context done", canceled, nil, wrap("read", os.NewSyscallError("read", syscall.ECONNRESET)), false},
+
+		{"other error", get, nil, errors.New("x509: certificate signed by unknown authority"), false},
+	}
+	for _, tt := range tests {
+		if got := ShouldRetry(tt.req, tt.res, tt.err); got != tt.want {
+			t.Errorf("%s: expected %v; got: %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		Err  error
+		Want bool
+	}{
+		{nil, false},
+		{&Error{Code: http.StatusServiceUnavailable}, true},
+		{&Error{Code: http.StatusTooManyRequests}, true},
+		{&Error{Code: http.StatusBadRequest}, false},
+		{&net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{context.Canceled, false},
+		{errors.New("invalid product"), false},
+	}
+	for i, tt := range tests {
+		if got := IsTransient(tt.Err); got != tt.Want {
+			t.Errorf("%d. expected %v for %v; got: %v", i, tt.Want, tt.Err, got)
+		}
+	}
+}
+
+func TestSendRetried(t *testing.T) {
+	defer func(d time.Duration) { RetryDelay = d }(RetryDelay)
+	RetryDelay = 0
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	req, err := http.NewRequest("DELETE", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Send(http.DefaultClient, req, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if Retried(res) {
+		t.Error("expected first attempt to not be retried")
+	}
+
+	requests = 0
+	res, err = Send(http.DefaultClient, req, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d; got: %d", http.StatusNotFound, res.StatusCode)
+	}
+	if !Retried(res) {
+		t.Error("expected response to be retried")
+	}
+	if Retried(nil) {
+		t.Error("expected nil response to not be retried")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		Header string
+		Want   time.Duration
+		OK     bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"99999999999999999", math.MaxInt64, true},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+	}
+	for _, tt := range tests {
+		res := &http.Response{Header: make(http.Header)}
+		if tt.Header != "" {
+			res.Header.Set("Retry-After", tt.Header)
+		}
+		got, ok := RetryAfter(res)
+		if got != tt.Want || ok != tt.OK {
+			t.Errorf("%q: expected %v, %v; got: %v, %v", tt.Header, tt.Want, tt.OK, got, ok)
+		}
+	}
+
+	res := &http.Response{Header: make(http.Header)}
+	res.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if got, ok := RetryAfter(res); !ok || got <= 59*time.Minute || got > time.Hour {
+		t.Errorf("expected about %v; got: %v, %v", time.Hour, got, ok)
+	}
+}
+
+func TestSendRetryAfter(t *testing.T) {
+	defer func(d time.Duration) { MaxRetryDelay = d }(MaxRetryDelay)
+	MaxRetryDelay = time.Minute
+
+	var requests int
+	retryAfter := "0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	req, err := http.NewRequest("POST", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Send(http.DefaultClient, req, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d; got: %d", http.StatusCreated, res.StatusCode)
+	}
+	if requests != 2 {
+		t.Fatalf("expected %d requests; got: %d", 2, requests)
+	}
+
+	// Do not wait longer than MaxRetryDelay
+	requests = 0
+	retryAfter = "3600"
+	res, err = Send(http.DefaultClient, req, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d; got: %d", http.StatusTooManyRequests, res.StatusCode)
+	}
+	if requests != 1 {
+		t.Fatalf("expected %d requests; got: %d", 1, requests)
+	}
+}
+
+func TestSendCountAttempts(t *testing.T) {
+	defer func(d time.Duration) { RetryDelay = d }(RetryDelay)
+	RetryDelay = 0
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	for _, tt := range []struct {
+		Retries int
+		Want    int
+	}{
+		{0, 1},
+		{1, 2},
+		{5, 3},
+	} {
+		requests = 0
+		req, err := http.NewRequest("GET", ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var attempts int
+		res, err := Send(http.DefaultClient, CountAttempts(req, &attempts), tt.Retries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if attempts != tt.Want {
+			t.Errorf("retries=%d: expected %d attempts; got: %d", tt.Retries, tt.Want, attempts)
+		}
+	}
+}
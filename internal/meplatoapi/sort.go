@@ -0,0 +1,40 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateSort checks that sort is a comma-separated list of the given
+// keys, each optionally prefixed with "-" for descending order. An empty
+// sort is valid and leaves the order up to the server.
+func ValidateSort(sort string, keys ...string) error {
+	if strings.TrimSpace(sort) == "" {
+		return nil
+	}
+	for _, field := range strings.Split(sort, ",") {
+		key := strings.TrimPrefix(strings.TrimSpace(field), "-")
+		found := false
+		for _, k := range keys {
+			if key == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid sort key %q (allowed: %s)", key, strings.Join(keys, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the layouts accepted by ParseTime, in the order they
+// are tried. Layouts without a time zone are interpreted as UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02",
+}
+
+// ParseTime parses a timestamp as returned by the API. Besides RFC 3339,
+// with or without fractional seconds, it accepts timestamps with a space
+// instead of the T, without a colon in the time zone offset or without a
+// time zone at all (as UTC), in RFC 1123 format like HTTP dates, and
+// plain dates like 2015-03-31.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as time", s)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// UnmarshalTimes decodes data into the struct v points to like
+// json.Unmarshal, but parses the time.Time and *time.Time fields of the
+// struct with ParseTime, so that timestamps in other formats than RFC 3339
+// do not fail. Use it in UnmarshalJSON with a type that has no methods, so
+// that it does not call itself, e.g.:
+//
+//	func (p *Product) UnmarshalJSON(data []byte) error {
+//		type product Product
+//		return meplatoapi.UnmarshalTimes(data, (*product)(p))
+//	}
+func UnmarshalTimes(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct || !isJSONObject(data) {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return err
+	}
+
+	// Parse the times ourselves and decode everything else with json
+	sv := rv.Elem()
+	times := make(map[int]time.Time)
+	for name, raw := range fields {
+		i := decodeField(sv.Type(), name)
+		if i < 0 {
+			continue
+		}
+		f := sv.Field(i)
+		if f.Type() != timeType && f.Type() != reflect.PtrTo(timeType) {
+			continue
+		}
+		delete(fields, name)
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("cannot decode %s: %v", name, err)
+		}
+		t, err := ParseTime(s)
+		if err != nil {
+			return fmt.Errorf("cannot decode %s: %v", name, err)
+		}
+		times[i] = t
+	}
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(rest, v); err != nil {
+		return err
+	}
+	for i, t := range times {
+		f := sv.Field(i)
+		if f.Kind() == reflect.Ptr {
+			t := t
+			f.Set(reflect.ValueOf(&t))
+		} else {
+			f.Set(reflect.ValueOf(t))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package meplatoapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	cet := time.FixedZone("", 2*60*60)
+	tests := []struct {
+		Input string
+		Want  time.Time
+	}{
+		{"2017-06-07T15:40:37+02:00", time.Date(2017, 6, 7, 15, 40, 37, 0, cet)},
+		{"2017-06-07T15:40:37.040890947+02:00", time.Date(2017, 6, 7, 15, 40, 37, 40890947, cet)},
+		{"2017-06-07T13:40:37Z", time.Date(2017, 6, 7, 13, 40, 37, 0, time.UTC)},
+		{"2017-06-07T13:40:37.5Z", time.Date(2017, 6, 7, 13, 40, 37, 500000000, time.UTC)},
+		{"2017-06-07T15:40:37+0200", time.Date(2017, 6, 7, 15, 40, 37, 0, cet)},
+		{"2017-06-07T13:40:37", time.Date(2017, 6, 7, 13, 40, 37, 0, time.UTC)},
+		{"2017-06-07T13:40:37.123", time.Date(2017, 6, 7, 13, 40, 37, 123000000, time.UTC)},
+		{"2017-06-07 15:40:37+02:00", time.Date(2017, 6, 7, 15, 40, 37, 0, cet)},
+		{"2017-06-07 15:40:37+0200", time.Date(2017, 6, 7, 15, 40, 37, 0, cet)},
+		{"2017-06-07 13:40:37", time.Date(2017, 6, 7, 13, 40, 37, 0, time.UTC)},
+		{"Wed, 07 Jun 2017 15:40:37 +0200", time.Date(2017, 6, 7, 15, 40, 37, 0, cet)},
+		{"Wed, 07 Jun 2017 13:40:37 UTC", time.Date(2017, 6, 7, 13, 40, 37, 0, time.UTC)},
+		{"2017-06-07", time.Date(2017, 6, 7, 0, 0, 0, 0, time.UTC)},
+		{" 2017-06-07 ", time.Date(2017, 6, 7, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, tt := range tests {
+		got, err := ParseTime(tt.Input)
+		if err != nil {
+			t.Errorf("%d. expected %q to parse; got: %v", i, tt.Input, err)
+			continue
+		}
+		if !got.Equal(tt.Want) {
+			t.Errorf("%d. expected %q to be %v; got: %v", i, tt.Input, tt.Want, got)
+		}
+	}
+
+	for _, input := range []string{"", "yesterday", "07.06.2017", "2017-13-01"} {
+		if _, err := ParseTime(input); err == nil {
+			t.Errorf("expected error for %q; got: nil", input)
+		}
+	}
+}
+
+// timesStruct is a struct with time fields to test UnmarshalTimes.
+type timesStruct struct {
+	Name      string     `json:"name"`
+	Created   *time.Time `json:"created,omitempty"`
+	Updated   *time.Time `json:"updated,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func TestUnmarshalTimes(t *testing.T) {
+	var v timesStruct
+	data := `{"name":"Produkt","created":"2017-06-07 13:40:37","updated":null,"CREATEDAT":"2017-06-07"}`
+	if err := UnmarshalTimes([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "Produkt" {
+		t.Errorf("expected name %q; got: %q", "Produkt", v.Name)
+	}
+	if v.Created == nil || !v.Created.Equal(time.Date(2017, 6, 7, 13, 40, 37, 0, time.UTC)) {
+		t.Errorf("expected created to be parsed; got: %v", v.Created)
+	}
+	if v.Updated != nil {
+		t.Errorf("expected updated to be nil; got: %v", v.Updated)
+	}
+	if !v.CreatedAt.Equal(time.Date(2017, 6, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected createdAt to be parsed; got: %v", v.CreatedAt)
+	}
+
+	for _, data := range []string{
+		`{"created":"yesterday"}`,
+		`{"created":42}`,
+		`{"name":42,"created":"2017-06-07"}`,
+		`{"name":`,
+	} {
+		var v timesStruct
+		if err := UnmarshalTimes([]byte(data), &v); err == nil {
+			t.Errorf("expected error for %s; got: nil", data)
+		} else if strings.Contains(data, "yesterday") && !strings.Contains(err.Error(), "created") {
+			t.Errorf("expected error to name the field; got: %v", err)
+		}
+	}
+}
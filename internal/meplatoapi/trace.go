@@ -0,0 +1,118 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings are the durations of the phases of a request.
+type Timings struct {
+	// DNS is the time it took to resolve the host name.
+	DNS time.Duration
+	// Connect is the time it took to establish the TCP connection.
+	Connect time.Duration
+	// TLS is the time it took to complete the TLS handshake.
+	TLS time.Duration
+	// TTFB is the time from starting the request until the first byte of
+	// the response was received, including the phases above.
+	TTFB time.Duration
+	// Reused reports whether an idle connection was reused, in which case
+	// DNS, Connect, and TLS are zero.
+	Reused bool
+}
+
+// TimingTrace returns a function that creates a ClientTrace for a request.
+// The trace measures the Timings of the request and passes them to
+// observe when the first byte of the response has been received. If a
+// request is retried, observe is called for every attempt.
+func TimingTrace(observe func(req *http.Request, t Timings)) func(req *http.Request) *httptrace.ClientTrace {
+	return func(req *http.Request) *httptrace.ClientTrace {
+		var (
+			mu                                   sync.Mutex
+			t                                    Timings
+			start, dnsStart, connStart, tlsStart time.Time
+		)
+		since := func(at time.Time) time.Duration {
+			if at.IsZero() {
+				return 0
+			}
+			return time.Since(at)
+		}
+		return &httptrace.ClientTrace{
+			GetConn: func(string) {
+				mu.Lock()
+				t, start = Timings{}, time.Now()
+				mu.Unlock()
+			},
+			GotConn: func(info httptrace.GotConnInfo) {
+				mu.Lock()
+				t.Reused = info.Reused
+				mu.Unlock()
+			},
+			DNSStart: func(httptrace.DNSStartInfo) {
+				mu.Lock()
+				dnsStart = time.Now()
+				mu.Unlock()
+			},
+			DNSDone: func(httptrace.DNSDoneInfo) {
+				mu.Lock()
+				t.DNS = since(dnsStart)
+				mu.Unlock()
+			},
+			ConnectStart: func(string, string) {
+				mu.Lock()
+				connStart = time.Now()
+				mu.Unlock()
+			},
+			ConnectDone: func(string, string, error) {
+				mu.Lock()
+				t.Connect = since(connStart)
+				mu.Unlock()
+			},
+			TLSHandshakeStart: func() {
+				mu.Lock()
+				tlsStart = time.Now()
+				mu.Unlock()
+			},
+			TLSHandshakeDone: func(tls.ConnectionState, error) {
+				mu.Lock()
+				t.TLS = since(tlsStart)
+				mu.Unlock()
+			},
+			GotFirstResponseByte: func() {
+				mu.Lock()
+				t.TTFB = since(start)
+				timings := t
+				mu.Unlock()
+				observe(req, timings)
+			},
+		}
+	}
+}
+
+// WithTrace returns req with the ClientTrace created by trace attached to
+// its context. It returns req unchanged if trace is nil.
+func WithTrace(req *http.Request, trace func(req *http.Request) *httptrace.ClientTrace) *http.Request {
+	if trace == nil {
+		return req
+	}
+	ct := trace(req)
+	if ct == nil {
+		return req
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), ct))
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"strings"
+)
+
+// JoinURL joins the base URL of a service, e.g.
+// https://store.meplato.com/api/v2, and the path of an endpoint. A
+// trailing slash of baseURL is ignored. If baseURL already ends with the
+// API version prefix that path starts with, e.g. /api/v2, the prefix is
+// only used once. This keeps URLs correct regardless of whether the
+// version is part of the base URL, the path, or both.
+func JoinURL(baseURL, path string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if prefix := versionPrefix(path); prefix != "" && strings.HasSuffix(baseURL, prefix) {
+		path = path[len(prefix):]
+	}
+	return baseURL + path
+}
+
+// versionPrefix returns the API version prefix of path, e.g. /api/v2 for
+// /api/v2/catalogs, or an empty string if path does not start with one.
+func versionPrefix(path string) string {
+	if !strings.HasPrefix(path, "/api/v") {
+		return ""
+	}
+	n := len("/api/v")
+	i := n
+	for i < len(path) && path[i] >= '0' && path[i] <= '9' {
+		i++
+	}
+	if i == n || (i < len(path) && path[i] != '/' && path[i] != '?') {
+		return ""
+	}
+	return path[:i]
+}
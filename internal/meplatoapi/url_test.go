@@ -0,0 +1,39 @@
+// Copyright (c) 2015 Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+package meplatoapi
+
+import (
+	"testing"
+)
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		BaseURL string
+		Path    string
+		Want    string
+	}{
+		{"https://store.meplato.com/api/v2", "/catalogs", "https://store.meplato.com/api/v2/catalogs"},
+		{"https://store.meplato.com/api/v2/", "/catalogs", "https://store.meplato.com/api/v2/catalogs"},
+		{"https://store.meplato.com/api/v2", "/api/v2/products/1/availabilities", "https://store.meplato.com/api/v2/products/1/availabilities"},
+		{"https://store.meplato.com/api/v2/", "/api/v2/products/1/availabilities?region=DE", "https://store.meplato.com/api/v2/products/1/availabilities?region=DE"},
+		{"https://store.meplato.com", "/api/v2/products/1/availabilities", "https://store.meplato.com/api/v2/products/1/availabilities"},
+		{"https://store.meplato.com/api/v2", "/api/v20/catalogs", "https://store.meplato.com/api/v2/api/v20/catalogs"},
+		{"https://store.meplato.com/api/v2", "/api/v2x/catalogs", "https://store.meplato.com/api/v2/api/v2x/catalogs"},
+		{"http://127.0.0.1:8080", "/catalogs{?q}", "http://127.0.0.1:8080/catalogs{?q}"},
+		{"", "/api/v2", "/api/v2"},
+	}
+	for i, test := range tests {
+		if got := JoinURL(test.BaseURL, test.Path); got != test.Want {
+			t.Errorf("#%d: expected %q; got: %q", i, test.Want, got)
+		}
+	}
+}
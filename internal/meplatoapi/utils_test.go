@@ -12,6 +12,9 @@
 package meplatoapi
 
 import (
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -114,3 +117,26 @@ func TestExpandURITemplate(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckResponseRestoresBody(t *testing.T) {
+	body := `{"error":{"code":503,"message":"Unavailable"}}`
+	res := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+	err := CheckResponse(res)
+	apiErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error; got: %T", err)
+	}
+	if apiErr.Body != body {
+		t.Errorf("expected error body %q; got: %q", body, apiErr.Body)
+	}
+	slurp, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(slurp) != body {
+		t.Errorf("expected body %q to be readable again; got: %q", body, string(slurp))
+	}
+}
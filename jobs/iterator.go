@@ -0,0 +1,38 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// Iterator iterates over the pages of jobs. Call Next until it reports
+// that there are no more pages.
+type Iterator = meplatoapi.Paginator[*Job]
+
+// Iterator returns an Iterator over the pages of the search result,
+// starting at Skip. The page size is set by Take.
+func (s *SearchService) Iterator() *Iterator {
+	skip, _ := s.opt_["skip"].(int64)
+	var total int64
+	return meplatoapi.NewPaginator(meplatoapi.SkipTake(skip, func(ctx context.Context, skip int64) ([]*Job, int64, error) {
+		res, err := s.Skip(skip).Do(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		total = res.TotalItems
+		return res.Items, res.TotalItems, nil
+	})).WithTotal(func() int64 { return total })
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
 	"strings"
@@ -60,8 +61,47 @@ type Service struct {
 	BaseURL  string
 	User     string
 	Password string
+	// MaxRetries is the number of times a request is retried on network
+	// errors and transient server errors like 503 (default 0).
+	MaxRetries int
+	// Debug logs every request and response, including the indented JSON
+	// bodies, to Logger. The Authorization header is redacted.
+	Debug bool
+	// Logger is used in debug mode. If nil, the standard logger is used.
+	Logger Logger
+	// MaxResponseBytes limits the size of response bodies. Reading a larger
+	// body fails with an error that matches store2.ErrResponseTooLarge
+	// (default 0, i.e. unlimited).
+	MaxResponseBytes int64
+	// Trace, if set, creates a ClientTrace for every request, e.g. to
+	// measure DNS, connect, TLS, and time-to-first-byte timings. See
+	// store2.TimingTrace for a ready-made implementation.
+	Trace func(req *http.Request) *httptrace.ClientTrace
+	// ContextHeaders maps context keys to the names of HTTP headers. If
+	// the context of a request has a value for a key, e.g. a tenant or
+	// correlation ID stored by middleware, it is sent in the mapped header
+	// without passing it to every operation. Values are formatted with
+	// fmt.Sprint. Headers set by an operation take precedence.
+	ContextHeaders map[interface{}]string
+	// ObserveAttempts, if set, is called after every request with the
+	// number of attempts it took, i.e. 1 if it was not retried, e.g. to
+	// record retries in your metrics and alert when the backend is flaky.
+	ObserveAttempts func(req *http.Request, attempts int)
+
+	limiter    meplatoapi.Limiter
+	middleware []Middleware
 }
 
+// Logger is used to log requests and responses in debug mode. It is
+// implemented by *log.Logger.
+type Logger = meplatoapi.Logger
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc = meplatoapi.RoundTripFunc
+
+// Middleware wraps the RoundTripFunc of a service. See Service.Use.
+type Middleware = meplatoapi.Middleware
+
 func New(client *http.Client) (*Service, error) {
 	if client == nil {
 		return nil, errors.New("client is nil")
@@ -69,6 +109,108 @@ func New(client *http.Client) (*Service, error) {
 	return &Service{client: client, BaseURL: baseURL}, nil
 }
 
+// NewFromEnv creates a new Service with a default HTTP client. The base
+// URL, user, and password are read from the STORE2_URL, STORE2_USER, and
+// STORE2_PASSWORD environment variables. If STORE2_URL is not set, the
+// default base URL is used. Debug logging is enabled if STORE2_DEBUG is
+// set to true.
+func NewFromEnv() (*Service, error) {
+	s, err := New(meplatoapi.NewDefaultClient())
+	if err != nil {
+		return nil, err
+	}
+	baseURL, user, password := meplatoapi.FromEnv()
+	if baseURL != "" {
+		s.BaseURL = baseURL
+	}
+	s.User = user
+	s.Password = password
+	s.Debug = meplatoapi.DebugFromEnv()
+	return s, nil
+}
+
+// SetMaxConcurrency limits the number of concurrent requests across all
+// operations of the service to n. Requests beyond the limit block until a
+// request finishes or their context is done. If n is less than or equal
+// to zero, the number of requests is not limited (default).
+func (s *Service) SetMaxConcurrency(n int) {
+	s.limiter.SetMax(n)
+}
+
+// WithProxy sends all requests of the service through the given proxy,
+// e.g. http://proxy.example.com:3128, so that services in one process can
+// use different proxies. It replaces the proxy from the HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY environment variables for this service; if
+// proxy is nil, requests are sent directly. The HTTP client of the
+// service is copied, so other users of the client are not affected. It
+// returns an error if the proxy URL is invalid or the client has a custom
+// transport that is not an *http.Transport.
+func (s *Service) WithProxy(proxy *url.URL) error {
+	client, err := meplatoapi.WithProxy(s.client, proxy)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+// InFlight returns the number of requests of the service that are
+// currently in flight.
+func (s *Service) InFlight() int {
+	return s.limiter.InFlight()
+}
+
+// Use adds middleware that wraps every request of the service, e.g. to
+// log, measure, cache, or rewrite requests and responses. Middleware runs
+// in the order it was added, i.e. the first one sees the request first
+// and the response last. It sees the request once, however often it is
+// retried, and the response body is already limited to MaxResponseBytes.
+// Use must not be called concurrently with requests.
+func (s *Service) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// do adds the headers of ContextHeaders to req, attaches the trace
+// created by Trace, and sends it through the middleware added by Use.
+func (s *Service) do(req *http.Request) (*http.Response, error) {
+	meplatoapi.SetContextHeaders(req, s.ContextHeaders)
+	req = meplatoapi.WithTrace(req, s.Trace)
+	return meplatoapi.Chain(s.send, s.middleware)(req)
+}
+
+// send sends req, retrying it according to MaxRetries and respecting the
+// limit set by SetMaxConcurrency. It reports the number of attempts to
+// ObserveAttempts, limits the response body to MaxResponseBytes, and, in
+// debug mode, logs the request and the response.
+func (s *Service) send(req *http.Request) (*http.Response, error) {
+	if s.Debug {
+		if err := meplatoapi.LogRequest(s.Logger, req); err != nil {
+			return nil, err
+		}
+	}
+	var attempts int
+	if s.ObserveAttempts != nil {
+		req = meplatoapi.CountAttempts(req, &attempts)
+	}
+	res, err := s.limiter.Send(s.client, req, s.MaxRetries)
+	if s.ObserveAttempts != nil {
+		s.ObserveAttempts(req, attempts)
+	}
+	if err == nil {
+		if err := meplatoapi.LimitBody(res, s.MaxResponseBytes); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	if err == nil && s.Debug {
+		if err := meplatoapi.LogResponse(s.Logger, res); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	return res, err
+}
+
 func (s *Service) Get() *GetService {
 	return NewGetService(s)
 }
@@ -118,6 +260,7 @@ type SearchResponse struct {
 	// Kind is store#jobs for this kind of response.
 	Kind string `json:"kind,omitempty"`
 	// NextLink returns the URL to the next slice of jobs (if any).
+	// If the response body has none, it is taken from the Link header.
 	NextLink string `json:"nextLink,omitempty"`
 	// PreviousLink returns the URL of the previous slice of jobs (if any).
 	PreviousLink string `json:"previousLink,omitempty"`
@@ -147,16 +290,26 @@ func (s *GetService) ID(id string) *GetService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *GetService) WithAuth(user, password string) *GetService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *GetService) Do(ctx context.Context) (*Job, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["id"] = s.id
+	if err := meplatoapi.RequireParams(params, "id"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/jobs/{id}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +321,10 @@ func (s *GetService) Do(ctx context.Context) (*Job, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +352,12 @@ func NewSearchService(s *Service) *SearchService {
 	return rs
 }
 
+// CatalogID filters the jobs by the ID of the catalog they belong to.
+func (s *SearchService) CatalogID(catalogID int64) *SearchService {
+	s.opt_["catalogId"] = catalogID
+	return s
+}
+
 // Skip specifies how many catalogs to skip (default 0).
 func (s *SearchService) Skip(skip int64) *SearchService {
 	s.opt_["skip"] = skip
@@ -214,10 +376,20 @@ func (s *SearchService) Take(take int64) *SearchService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *SearchService) WithAuth(user, password string) *SearchService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
+	if v, ok := s.opt_["catalogId"]; ok {
+		params["catalogId"] = v
+	}
 	if v, ok := s.opt_["skip"]; ok {
 		params["skip"] = v
 	}
@@ -227,11 +399,11 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if v, ok := s.opt_["take"]; ok {
 		params["take"] = v
 	}
-	path, err := meplatoapi.Expand("/jobs{?merchantId,skip,take,state}", params)
+	path, err := meplatoapi.Expand("/jobs{?merchantId,catalogId,skip,take,state}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -243,7 +415,10 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -255,5 +430,12 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
 		return nil, err
 	}
+	next, prev := meplatoapi.PageLinks(res)
+	if ret.NextLink == "" {
+		ret.NextLink = next
+	}
+	if ret.PreviousLink == "" {
+		ret.PreviousLink = prev
+	}
 	return ret, nil
 }
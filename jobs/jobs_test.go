@@ -1,49 +1,24 @@
 package jobs_test
 
 import (
-	"bufio"
 	"context"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"path"
-	"strings"
 	"testing"
 
 	"github.com/meplato/store2-go-client/v2/jobs"
+	"github.com/meplato/store2-go-client/v2/storetest"
 )
 
 func getService(responseFile string) (*jobs.Service, *httptest.Server, error) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slurp, err := ioutil.ReadFile(path.Join("testdata", responseFile))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(string(slurp))), r)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer res.Body.Close()
-		bs, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(res.StatusCode)
-		fmt.Fprint(w, string(bs))
-	}))
+	ts := storetest.ReplayServer(path.Join("testdata", responseFile))
 
-	service, err := jobs.New(http.DefaultClient)
+	service, err := jobs.NewFromEnv()
 	if err != nil {
 		return service, nil, err
 	}
 	service.BaseURL = ts.URL
-	service.User = os.Getenv("STORE2_USER")
-	service.Password = os.Getenv("STORE2_PASSWORD")
 	return service, ts, nil
 }
 
@@ -66,6 +41,34 @@ func TestJobsSearch(t *testing.T) {
 	}
 }
 
+func TestJobsSearchByCatalogID(t *testing.T) {
+	var query string
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		query = r.URL.RawQuery
+		return path.Join("testdata", "jobs.search.catalog")
+	})
+	defer ts.Close()
+	service, err := jobs.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	res, err := service.Search().CatalogID(57).Take(10).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "catalogId=57&take=10"; query != want {
+		t.Errorf("expected query %q; got: %q", want, query)
+	}
+	if res.TotalItems != 1 || len(res.Items) != 1 {
+		t.Fatalf("expected %d job; got: %d", 1, len(res.Items))
+	}
+	if got := res.Items[0].CatalogID; got != 57 {
+		t.Errorf("expected catalog ID %d; got: %d", 57, got)
+	}
+}
+
 func TestJobGet(t *testing.T) {
 	service, ts, err := getService("jobs.get.success")
 	if err != nil {
@@ -89,4 +92,10 @@ func TestJobGet(t *testing.T) {
 	if job.ID != "58097dc3-b279-49b5-a5da-23eb1c77d840" {
 		t.Errorf("expected %q; got: %q", "58097dc3-b279-49b5-a5da-23eb1c77d840", job.ID)
 	}
+	if job.Topic != jobs.TopicValidateProject {
+		t.Errorf("expected %q; got: %q", jobs.TopicValidateProject, job.Topic)
+	}
+	if !job.IsTerminal() || !job.IsSucceeded() {
+		t.Errorf("expected job to have succeeded; got state %q", job.State)
+	}
 }
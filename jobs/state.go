@@ -0,0 +1,48 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package jobs
+
+// States of a job, see the State field of a job.
+const (
+	StateWaiting   = "waiting"
+	StateWorking   = "working"
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+)
+
+// Topics of a job, see the Topic field of a job. The list is not
+// exhaustive, as the server may add new kinds of tasks.
+const (
+	// TopicBMEcatImport is the topic of a job that imports a BMEcat file
+	// into a catalog.
+	TopicBMEcatImport = "CatalogBMEcatImportTask"
+	// TopicValidateProject is the topic of a job that validates a catalog
+	// against the rules of its project.
+	TopicValidateProject = "CatalogValidateProjectTask"
+)
+
+// IsTerminal reports whether the job has finished, i.e. it either
+// succeeded or failed, and its state will not change anymore.
+func (j *Job) IsTerminal() bool {
+	return j.State == StateSucceeded || j.State == StateFailed
+}
+
+// IsFailed reports whether the job has failed.
+func (j *Job) IsFailed() bool {
+	return j.State == StateFailed
+}
+
+// IsSucceeded reports whether the job has succeeded.
+func (j *Job) IsSucceeded() bool {
+	return j.State == StateSucceeded
+}
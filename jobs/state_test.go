@@ -0,0 +1,34 @@
+package jobs_test
+
+import (
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/jobs"
+)
+
+func TestJobStatePredicates(t *testing.T) {
+	tests := []struct {
+		State     string
+		Terminal  bool
+		Failed    bool
+		Succeeded bool
+	}{
+		{jobs.StateWaiting, false, false, false},
+		{jobs.StateWorking, false, false, false},
+		{jobs.StateSucceeded, true, false, true},
+		{jobs.StateFailed, true, true, false},
+		{"", false, false, false},
+	}
+	for _, test := range tests {
+		job := &jobs.Job{State: test.State}
+		if got := job.IsTerminal(); got != test.Terminal {
+			t.Errorf("%q: expected IsTerminal=%v; got: %v", test.State, test.Terminal, got)
+		}
+		if got := job.IsFailed(); got != test.Failed {
+			t.Errorf("%q: expected IsFailed=%v; got: %v", test.State, test.Failed, got)
+		}
+		if got := job.IsSucceeded(); got != test.Succeeded {
+			t.Errorf("%q: expected IsSucceeded=%v; got: %v", test.State, test.Succeeded, got)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package jobs_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/jobs"
+)
+
+func TestJobUnmarshalTimes(t *testing.T) {
+	data := `{
+		"id": "58097dc3-b279-49b5-a5da-23eb1c77d840",
+		"created": "2017-06-07T15:40:37.040890947+02:00",
+		"started": "2017-06-07 15:40:38+0200",
+		"completed": "2017-06-07T13:40:39"
+	}`
+	var job jobs.Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "58097dc3-b279-49b5-a5da-23eb1c77d840" {
+		t.Errorf("expected ID to be decoded; got: %q", job.ID)
+	}
+	for name, tt := range map[string]struct {
+		Got  *time.Time
+		Want time.Time
+	}{
+		"created":   {job.Created, time.Date(2017, 6, 7, 13, 40, 37, 40890947, time.UTC)},
+		"started":   {job.Started, time.Date(2017, 6, 7, 13, 40, 38, 0, time.UTC)},
+		"completed": {job.Completed, time.Date(2017, 6, 7, 13, 40, 39, 0, time.UTC)},
+	} {
+		if tt.Got == nil || !tt.Got.Equal(tt.Want) {
+			t.Errorf("expected %s %v; got: %v", name, tt.Want, tt.Got)
+		}
+	}
+}
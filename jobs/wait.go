@@ -0,0 +1,69 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitInterval is the poll interval used by WaitForState if no
+// positive interval is given.
+const DefaultWaitInterval = 5 * time.Second
+
+// ErrUnexpectedState is returned by WaitForState if the job has finished
+// in a state other than the ones waited for.
+var ErrUnexpectedState = errors.New("jobs: job finished in unexpected state")
+
+// WaitForState polls the job with the given ID every poll interval until
+// its state is one of states, e.g. StateSucceeded. If no states are
+// given, it waits until the job has finished, see Job.IsTerminal. It
+// returns the job in its final state. If the job finishes in a state
+// not in states, e.g. StateFailed while waiting for StateSucceeded,
+// WaitForState returns the job together with an error wrapping
+// ErrUnexpectedState. If ctx is done before, WaitForState returns the
+// context error.
+//
+// Write operations that trigger a job return its ID in the JobID field of
+// their response, e.g. catalogs.PublishResponse.
+func (s *Service) WaitForState(ctx context.Context, id string, poll time.Duration, states ...string) (*Job, error) {
+	if poll <= 0 {
+		poll = DefaultWaitInterval
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		job, err := s.Get().ID(id).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(states) == 0 && job.IsTerminal() {
+			return job, nil
+		}
+		for _, state := range states {
+			if job.State == state {
+				return job, nil
+			}
+		}
+		if job.IsTerminal() {
+			return job, fmt.Errorf("%w: job %s is %q", ErrUnexpectedState, job.ID, job.State)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
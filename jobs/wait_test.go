@@ -0,0 +1,77 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/jobs"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestJobsWaitForState(t *testing.T) {
+	var requests int
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		requests++
+		if requests < 3 {
+			return path.Join("testdata", "jobs.get.working")
+		}
+		return path.Join("testdata", "jobs.get.success")
+	})
+	defer ts.Close()
+	service, err := jobs.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	job, err := service.WaitForState(context.Background(), "58097dc3-b279-49b5-a5da-23eb1c77d840", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != jobs.StateSucceeded {
+		t.Errorf("expected state %q; got: %q", jobs.StateSucceeded, job.State)
+	}
+	if requests != 3 {
+		t.Errorf("expected %d requests; got: %d", 3, requests)
+	}
+}
+
+func TestJobsWaitForStateCanceled(t *testing.T) {
+	service, ts, err := getService("jobs.get.working")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = service.WaitForState(ctx, "58097dc3-b279-49b5-a5da-23eb1c77d840", time.Millisecond, jobs.StateSucceeded)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v; got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestJobsWaitForStateUnexpected(t *testing.T) {
+	service, ts, err := getService("jobs.get.failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := service.WaitForState(ctx, "58097dc3-b279-49b5-a5da-23eb1c77d840", time.Millisecond, jobs.StateSucceeded)
+	if !errors.Is(err, jobs.ErrUnexpectedState) {
+		t.Fatalf("expected %v; got: %v", jobs.ErrUnexpectedState, err)
+	}
+	if job == nil {
+		t.Fatal("expected job; got: nil")
+	}
+	if job.State != jobs.StateFailed {
+		t.Errorf("expected state %q; got: %q", jobs.StateFailed, job.State)
+	}
+}
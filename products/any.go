@@ -0,0 +1,28 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+)
+
+// Any reports whether the search has at least one result. It requests a
+// single product starting at the first one, so the Skip and Take options
+// of s are overwritten.
+func (s *SearchService) Any(ctx context.Context) (bool, error) {
+	res, err := s.Skip(0).Take(1).Do(ctx)
+	if err != nil {
+		return false, err
+	}
+	return res.TotalItems > 0 || len(res.Items) > 0, nil
+}
@@ -0,0 +1,41 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProductSearchAny(t *testing.T) {
+	var query string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		query = r.URL.RawQuery
+		if r.URL.Query().Get("q") == "nothing" {
+			return "products.search.empty"
+		}
+		return "products.search.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	any, err := service.Search().PIN("AD8CCDD5F9").Area("work").Q("apple").Skip(40).Any(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !any {
+		t.Fatal("expected a result; got: none")
+	}
+	if want := "q=apple&skip=0&take=1"; query != want {
+		t.Fatalf("expected query %q; got: %q", want, query)
+	}
+
+	any, err = service.Search().PIN("AD8CCDD5F9").Area("work").Q("nothing").Any(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if any {
+		t.Fatal("expected no result; got: some")
+	}
+}
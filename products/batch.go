@@ -0,0 +1,145 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// DefaultBatchWorkers is the number of concurrent requests used by the
+// batch services unless configured otherwise.
+const DefaultBatchWorkers = 4
+
+func (s *Service) BatchGet() *BatchGetService {
+	return NewBatchGetService(s)
+}
+
+// BatchGetResponse is the outcome of getting multiple products at once.
+type BatchGetResponse struct {
+	// Items contains the products that were found, indexed by SPN.
+	Items map[string]*Product
+	// NotFound lists the SPNs that do not exist in the catalog, in the
+	// order they were requested.
+	NotFound []string
+}
+
+// BatchGet returns several products of a catalog by their Supplier Part
+// Numbers (SPN). It issues one Get per SPN with bounded concurrency.
+type BatchGetService struct {
+	s       *Service
+	hdr_    map[string]interface{}
+	pin     string
+	area    string
+	spns    []string
+	workers int
+}
+
+// NewBatchGetService creates a new instance of BatchGetService.
+func NewBatchGetService(s *Service) *BatchGetService {
+	rs := &BatchGetService{s: s, hdr_: make(map[string]interface{}), workers: DefaultBatchWorkers}
+	return rs
+}
+
+// Area of the catalog, e.g. work or live.
+func (s *BatchGetService) Area(area string) *BatchGetService {
+	s.area = area
+	return s
+}
+
+// PIN of the catalog.
+func (s *BatchGetService) PIN(pin string) *BatchGetService {
+	s.pin = pin
+	return s
+}
+
+// Spns are the supplier part numbers of the products to get. Duplicates
+// are fetched only once.
+func (s *BatchGetService) Spns(spns []string) *BatchGetService {
+	s.spns = spns
+	return s
+}
+
+// Workers is the maximum number of concurrent requests (default 4).
+func (s *BatchGetService) Workers(workers int) *BatchGetService {
+	s.workers = workers
+	return s
+}
+
+// WithAuth overrides the user and password of the service for the
+// requests of this operation only.
+func (s *BatchGetService) WithAuth(user, password string) *BatchGetService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// Do executes the operation. It returns the first error other than
+// "not found" that occurs. If ctx is done before all products have been
+// fetched, it returns the products fetched so far together with the
+// context error.
+func (s *BatchGetService) Do(ctx context.Context) (*BatchGetResponse, error) {
+	var spns []string
+	seen := make(map[string]bool)
+	for _, spn := range s.spns {
+		if !seen[spn] {
+			seen[spn] = true
+			spns = append(spns, spn)
+		}
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	items := make([]*Product, len(spns))
+	errs := make([]error, len(spns))
+	meplatoapi.ForEach(ctx, len(spns), s.workers, func(ctx context.Context, i int) {
+		get := s.s.Get().PIN(s.pin).Area(s.area).Spn(spns[i])
+		for k, v := range s.hdr_ {
+			get.hdr_[k] = v
+		}
+		items[i], errs[i] = get.Do(ctx)
+		if errs[i] != nil && !isNotFound(errs[i]) {
+			cancel()
+		}
+	})
+
+	ret := &BatchGetResponse{Items: make(map[string]*Product)}
+	var incomplete bool
+	for i, spn := range spns {
+		switch {
+		case errs[i] == nil && items[i] != nil:
+			ret.Items[spn] = items[i]
+		case isNotFound(errs[i]):
+			ret.NotFound = append(ret.NotFound, spn)
+		case errs[i] == nil, errors.Is(errs[i], context.Canceled), errors.Is(errs[i], context.DeadlineExceeded):
+			// Not fetched because ctx is done
+			incomplete = true
+		default:
+			return nil, errs[i]
+		}
+	}
+	if incomplete && parent.Err() != nil {
+		return ret, parent.Err()
+	}
+	return ret, nil
+}
+
+// isNotFound reports whether err is a 404 response from the server.
+func isNotFound(err error) bool {
+	var e *meplatoapi.Error
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
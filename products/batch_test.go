@@ -0,0 +1,99 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProductBatchGet(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if strings.HasSuffix(r.URL.Path, "/products/50763599") {
+			return "products.get.success"
+		}
+		return "products.get.not_found"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	spns := []string{"50763599", "no-such-spn", "50763599", "another-missing-spn"}
+	res, err := service.BatchGet().PIN("AD8CCDD5F9").Area("work").Spns(spns).Workers(2).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil {
+		t.Fatal("expected response; got: nil")
+	}
+	if want, got := 1, len(res.Items); want != got {
+		t.Fatalf("expected %d item(s); got: %d", want, got)
+	}
+	if p := res.Items["50763599"]; p == nil || p.Spn != "50763599" {
+		t.Fatalf("expected product %q; got: %v", "50763599", p)
+	}
+	if want, got := "no-such-spn,another-missing-spn", strings.Join(res.NotFound, ","); want != got {
+		t.Fatalf("expected not found %q; got: %q", want, got)
+	}
+}
+
+func TestProductBatchGetCanceled(t *testing.T) {
+	service, ts, err := getService("products.get.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := service.BatchGet().PIN("AD8CCDD5F9").Area("work").Spns([]string{"1", "2", "3"}).Do(ctx)
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+	if res == nil {
+		t.Fatal("expected partial response; got: nil")
+	}
+	if len(res.Items) != 0 {
+		t.Fatalf("expected no items; got: %d", len(res.Items))
+	}
+}
+
+func TestProductBatchGetCanceledPartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int32
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if atomic.AddInt32(&requests, 1) == 2 {
+			cancel()
+		}
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.BatchGet().PIN("AD8CCDD5F9").Area("work").Spns([]string{"1", "2", "3"}).Workers(1).Do(ctx)
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+	if res == nil {
+		t.Fatal("expected partial response; got: nil")
+	}
+	if p := res.Items["1"]; p == nil {
+		t.Fatalf("expected product %q to be fetched; got: %v", "1", res.Items)
+	}
+	if _, found := res.Items["3"]; found {
+		t.Fatalf("expected product %q not to be fetched", "3")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+func (s *Service) BatchUpsert() *BatchUpsertService {
+	return NewBatchUpsertService(s)
+}
+
+// BatchUpsertItem is the outcome of upserting a single product of a
+// batch.
+type BatchUpsertItem struct {
+	// Index is the position of the product in the batch.
+	Index int
+	// Product is the product that has been sent.
+	Product *UpsertProduct
+	// Response is the response of the server if the upsert succeeded.
+	Response *UpsertProductResponse
+	// Err is the error if the upsert failed.
+	Err error
+}
+
+// BatchUpsertResponse is the outcome of upserting multiple products at
+// once.
+type BatchUpsertResponse struct {
+	// Items contains the outcome for every product, in the order of the
+	// batch.
+	Items []*BatchUpsertItem
+}
+
+// Failed returns the items whose upsert failed.
+func (r *BatchUpsertResponse) Failed() []*BatchUpsertItem {
+	var failed []*BatchUpsertItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// BatchUpsert creates or updates several products of a catalog. It issues
+// one Upsert per product with bounded concurrency. Unlike BatchGet, it
+// does not stop at the first error but reports the outcome for every
+// product, so that RetryFailed can resubmit the failed ones only.
+type BatchUpsertService struct {
+	s        *Service
+	hdr_     map[string]interface{}
+	pin      string
+	area     string
+	products []*UpsertProduct
+	workers  int
+}
+
+// NewBatchUpsertService creates a new instance of BatchUpsertService.
+func NewBatchUpsertService(s *Service) *BatchUpsertService {
+	rs := &BatchUpsertService{s: s, hdr_: make(map[string]interface{}), workers: DefaultBatchWorkers}
+	return rs
+}
+
+// Area of the catalog, e.g. work or live.
+func (s *BatchUpsertService) Area(area string) *BatchUpsertService {
+	s.area = area
+	return s
+}
+
+// PIN of the catalog.
+func (s *BatchUpsertService) PIN(pin string) *BatchUpsertService {
+	s.pin = pin
+	return s
+}
+
+// Products to create or update.
+func (s *BatchUpsertService) Products(products []*UpsertProduct) *BatchUpsertService {
+	s.products = products
+	return s
+}
+
+// Workers is the maximum number of concurrent requests (default 4).
+func (s *BatchUpsertService) Workers(workers int) *BatchUpsertService {
+	s.workers = workers
+	return s
+}
+
+// WithAuth overrides the user and password of the service for the
+// requests of this operation only.
+func (s *BatchUpsertService) WithAuth(user, password string) *BatchUpsertService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// Do executes the operation. Errors of single products are reported in
+// the items of the response. Do only returns an error if ctx is done
+// before all products have been sent. In that case, it returns the
+// response together with the context error, and the products that have
+// not been sent report the context error in their items.
+func (s *BatchUpsertService) Do(ctx context.Context) (*BatchUpsertResponse, error) {
+	ret := &BatchUpsertResponse{Items: make([]*BatchUpsertItem, len(s.products))}
+	for i, p := range s.products {
+		ret.Items[i] = &BatchUpsertItem{Index: i, Product: p}
+	}
+	if err := s.upsert(ctx, ret.Items); err != nil {
+		return ret, err
+	}
+	return ret, nil
+}
+
+// RetryFailed resubmits the failed items of res whose error is transient,
+// e.g. a 503 response or a network error, and updates them in place.
+// Items that failed permanently, e.g. because the product is invalid, are
+// not resubmitted. Requests are retried according to MaxRetries of the
+// service as usual. RetryFailed returns res, together with the context
+// error if ctx is done before all failed items have been resubmitted.
+func (s *BatchUpsertService) RetryFailed(ctx context.Context, res *BatchUpsertResponse) (*BatchUpsertResponse, error) {
+	var items []*BatchUpsertItem
+	for _, item := range res.Failed() {
+		if meplatoapi.IsTransient(item.Err) {
+			items = append(items, item)
+		}
+	}
+	if err := s.upsert(ctx, items); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// upsert sends the products of items and records the outcome in items.
+// Items that have not been sent because ctx is done report the context
+// error.
+func (s *BatchUpsertService) upsert(ctx context.Context, items []*BatchUpsertItem) error {
+	sent := make([]bool, len(items))
+	err := meplatoapi.ForEach(ctx, len(items), s.workers, func(ctx context.Context, i int) {
+		sent[i] = true
+		item := items[i]
+		upsert := s.s.Upsert().PIN(s.pin).Area(s.area).Product(item.Product)
+		for k, v := range s.hdr_ {
+			upsert.hdr_[k] = v
+		}
+		item.Response, item.Err = upsert.Do(ctx)
+	})
+	if err != nil {
+		for i, item := range items {
+			if !sent[i] {
+				item.Err = err
+			}
+		}
+	}
+	return err
+}
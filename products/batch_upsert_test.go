@@ -0,0 +1,109 @@
+package products_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductBatchUpsertRetryFailed(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		var p products.UpsertProduct
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		requests[p.Spn]++
+		n := requests[p.Spn]
+		mu.Unlock()
+		switch {
+		case p.Spn == "2000" && n == 1:
+			return "products.create.unavailable"
+		case p.Spn == "3000":
+			return "products.create.parameter_missing"
+		}
+		return "products.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	batch := []*products.UpsertProduct{
+		{Spn: "1000", Name: "Product 1000", Price: 9.99, OrderUnit: "PCE"},
+		{Spn: "2000", Name: "Product 2000", Price: 19.99, OrderUnit: "PCE"},
+		{Spn: "3000", Name: "Product 3000", Price: 29.99},
+	}
+	svc := service.BatchUpsert().PIN("AD8CCDD5F9").Area("work").Products(batch)
+	res, err := svc.Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 3 {
+		t.Fatalf("expected %d items; got: %d", 3, len(res.Items))
+	}
+	failed := res.Failed()
+	if len(failed) != 2 || failed[0].Index != 1 || failed[1].Index != 2 {
+		t.Fatalf("expected items 1 and 2 to fail; got: %v", failed)
+	}
+	if failed[0].Product != batch[1] {
+		t.Errorf("expected failed item to refer to the original product")
+	}
+
+	res, err = svc.RetryFailed(context.Background(), res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed = res.Failed()
+	if len(failed) != 1 || failed[0].Index != 2 {
+		t.Fatalf("expected only item 2 to fail after retry; got: %v", failed)
+	}
+	if res.Items[1].Err != nil || res.Items[1].Response == nil {
+		t.Errorf("expected item 1 to succeed after retry; got: %v", res.Items[1].Err)
+	}
+	if requests["1000"] != 1 || requests["2000"] != 2 || requests["3000"] != 1 {
+		t.Errorf("expected only the transient failure to be resubmitted; got: %v", requests)
+	}
+}
+
+func TestProductBatchUpsertCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int32
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			cancel()
+		}
+		return "products.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	batch := []*products.UpsertProduct{
+		{Spn: "1000", Name: "Product 1000", Price: 9.99, OrderUnit: "PCE"},
+		{Spn: "2000", Name: "Product 2000", Price: 19.99, OrderUnit: "PCE"},
+	}
+	res, err := service.BatchUpsert().PIN("AD8CCDD5F9").Area("work").Products(batch).Workers(1).Do(ctx)
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got: %v", context.Canceled, err)
+	}
+	if res == nil {
+		t.Fatal("expected partial response; got: nil")
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected %d items; got: %d", 2, len(res.Items))
+	}
+	if item := res.Items[1]; item.Err != context.Canceled {
+		t.Errorf("expected item 1 to report %v; got: %v", context.Canceled, item.Err)
+	}
+}
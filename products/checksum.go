@@ -0,0 +1,58 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Checksum computes a checksum of a set of products, e.g. to detect
+// whether the local product data has changed since the last upload and
+// skip the upload if it has not. The checksum does not depend on the
+// order of products; nil products are ignored.
+//
+// The hashing scheme is as follows: each product is encoded as JSON,
+// exactly as it would be sent to the server, i.e. with fields in the
+// order of the struct and empty fields omitted, and hashed with SHA-256.
+// The hashes are sorted, concatenated, and hashed with SHA-256 again.
+// Checksum returns the result as a lowercase hex string. A product that
+// cannot be encoded as JSON is hashed by its SPN and the error.
+//
+// The checksum is computed locally and cannot be compared to the
+// DownloadChecksum of a catalog, which is computed by the server.
+func Checksum(products []*UpsertProduct) string {
+	var hashes [][sha256.Size]byte
+	for _, p := range products {
+		if p == nil {
+			continue
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			// The product cannot be sent either, e.g. because a number
+			// is NaN, so the SPN and the error must do
+			data = []byte(p.Spn + "\x00" + err.Error())
+		}
+		hashes = append(hashes, sha256.Sum256(data))
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return string(hashes[i][:]) < string(hashes[j][:])
+	})
+	h := sha256.New()
+	for _, sum := range hashes {
+		h.Write(sum[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
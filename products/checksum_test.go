@@ -0,0 +1,44 @@
+package products_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestChecksum(t *testing.T) {
+	a := &products.UpsertProduct{Spn: "1000", Name: "Product 1000", Price: 9.99, OrderUnit: "PCE"}
+	b := &products.UpsertProduct{Spn: "2000", Name: "Product 2000", Price: 19.99, OrderUnit: "PCE"}
+
+	sum := products.Checksum([]*products.UpsertProduct{a, b})
+	if len(sum) != 64 {
+		t.Fatalf("expected a hex-encoded SHA-256; got: %q", sum)
+	}
+	if got := products.Checksum([]*products.UpsertProduct{b, nil, a}); got != sum {
+		t.Errorf("expected checksum to not depend on order; got: %q and %q", sum, got)
+	}
+
+	// A copy has the same checksum
+	c := *a
+	if got := products.Checksum([]*products.UpsertProduct{&c, b}); got != sum {
+		t.Errorf("expected checksum of equal products to match; got: %q and %q", sum, got)
+	}
+
+	c.Price = 10.99
+	if got := products.Checksum([]*products.UpsertProduct{&c, b}); got == sum {
+		t.Error("expected checksum to change with the price")
+	}
+	if got := products.Checksum([]*products.UpsertProduct{a}); got == sum {
+		t.Error("expected checksum to change with the number of products")
+	}
+	if products.Checksum(nil) != products.Checksum([]*products.UpsertProduct{}) {
+		t.Error("expected checksum of nil and empty sets to match")
+	}
+
+	// Products that cannot be encoded still get a stable checksum
+	c.Price = math.NaN()
+	if products.Checksum([]*products.UpsertProduct{&c}) != products.Checksum([]*products.UpsertProduct{&c}) {
+		t.Error("expected checksum to be stable for products that cannot be encoded")
+	}
+}
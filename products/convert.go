@@ -0,0 +1,59 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProductFrom uses the product p, as returned by e.g. Get or Search, as
+// the new properties of the product. Read-only fields like ID, Created,
+// or SelfLink are dropped. If no SPN has been set yet, the SPN of p is used.
+func (s *ReplaceService) ProductFrom(p *Product) *ReplaceService {
+	product := new(ReplaceProduct)
+	s.err = convertProduct(p, product)
+	s.product = product
+	if s.spn == "" && p != nil {
+		s.spn = p.Spn
+	}
+	return s
+}
+
+// ProductFrom uses the product p, as returned by e.g. Get or Search, as
+// the product to create or update. Read-only fields like ID, Created, or
+// SelfLink are dropped.
+func (s *UpsertService) ProductFrom(p *Product) *UpsertService {
+	product := new(UpsertProduct)
+	s.err = convertProduct(p, product)
+	s.product = product
+	return s
+}
+
+// convertProduct copies the writable fields of the read model p into dst,
+// which must be a pointer to one of the write models like ReplaceProduct.
+// Fields are matched by their JSON names, so read-only fields of p are
+// dropped and value fields are converted to pointers where necessary.
+func convertProduct(p *Product, dst interface{}) error {
+	if p == nil {
+		return nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("products: cannot convert product: %v", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("products: cannot convert product: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package products_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductReplaceProductFrom(t *testing.T) {
+	var sent map[string]interface{}
+	var sentPath string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.Method == "GET" {
+			return "products.get.success"
+		}
+		sentPath = r.URL.Path
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(data, &sent)
+		}
+		if err != nil {
+			t.Errorf("cannot read request body: %v", err)
+		}
+		return "products.replace.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Name = "Changed name"
+
+	rres, err := service.Replace().PIN("AD8CCDD5F9").Area("work").ProductFrom(p).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rres == nil {
+		t.Fatal("expected response; got: nil")
+	}
+	if want := "/products/" + p.Spn; !strings.HasSuffix(sentPath, want) {
+		t.Errorf("expected path to end with %q; got: %q", want, sentPath)
+	}
+	if sent["name"] != "Changed name" {
+		t.Errorf("expected name %q; got: %v", "Changed name", sent["name"])
+	}
+	if sent["price"] != p.Price {
+		t.Errorf("expected price %v; got: %v", p.Price, sent["price"])
+	}
+	if sent["ou"] != p.OrderUnit {
+		t.Errorf("expected ou %q; got: %v", p.OrderUnit, sent["ou"])
+	}
+	for _, field := range []string{"id", "kind", "created", "updated", "selfLink", "spn", "catalogId"} {
+		if v, found := sent[field]; found {
+			t.Errorf("expected read-only field %q to be dropped; got: %v", field, v)
+		}
+	}
+}
+
+func TestProductReplaceProductFromKeepsSpn(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if !strings.HasSuffix(r.URL.Path, "/products/OTHER") {
+			t.Errorf("expected explicit SPN in path; got: %q", r.URL.Path)
+		}
+		return "products.replace.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := &products.Product{Spn: "MBA11", Name: "Product", Price: 1.5, OrderUnit: "PCE"}
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("OTHER").ProductFrom(p).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProductUpsertProductFrom(t *testing.T) {
+	var sent map[string]interface{}
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.Method == "GET" {
+			return "products.get.success"
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(data, &sent)
+		}
+		if err != nil {
+			t.Errorf("cannot read request body: %v", err)
+		}
+		return "products.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Price = 9.99
+
+	ures, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").ProductFrom(p).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ures == nil {
+		t.Fatal("expected response; got: nil")
+	}
+	for field, want := range map[string]interface{}{"spn": p.Spn, "name": p.Name, "price": 9.99, "ou": p.OrderUnit} {
+		if sent[field] != want {
+			t.Errorf("expected %s %v; got: %v", field, want, sent[field])
+		}
+	}
+	for _, field := range []string{"id", "kind", "created", "updated", "selfLink", "catalogId"} {
+		if v, found := sent[field]; found {
+			t.Errorf("expected read-only field %q to be dropped; got: %v", field, v)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestServiceContextHeaders(t *testing.T) {
+	var tenants []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		tenants = append(tenants, r.Header.Get("X-Tenant-ID"))
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.ContextHeaders = map[interface{}]string{tenantKey{}: "X-Tenant-ID"}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	if _, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "" {
+		t.Fatalf("expected tenant headers [acme ]; got: %q", tenants)
+	}
+}
@@ -0,0 +1,47 @@
+package products_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductDebug(t *testing.T) {
+	service, ts, err := getService("products.create.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	service.Debug = true
+	service.Logger = log.New(&buf, "", 0)
+	service.User = "secret-token"
+
+	create := &products.CreateProduct{Spn: "MBA11", Name: "Apple MacBook Air 11\"", Price: 1299.00, OrderUnit: "PCE"}
+	res, err := service.Create().PIN("AD8CCDD5F9").Area("work").Product(create).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil {
+		t.Fatal("expected response; got: nil")
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "secret-token") || strings.Contains(out, "c2VjcmV0LXRva2Vu") {
+		t.Fatalf("expected Authorization to be redacted; got:\n%s", out)
+	}
+	if !strings.Contains(out, "Authorization: REDACTED") {
+		t.Fatalf("expected redacted Authorization header; got:\n%s", out)
+	}
+	if !strings.Contains(out, "\n  \"spn\": \"MBA11\"") {
+		t.Fatalf("expected indented request body; got:\n%s", out)
+	}
+	if !strings.Contains(out, "\"kind\": \"store#productsCreateResponse\"") {
+		t.Fatalf("expected response body; got:\n%s", out)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// DecodeWarning describes a value of a response that could not be decoded
+// into its field if Service.LenientDecode is set. The field is left empty.
+type DecodeWarning = meplatoapi.DecodeWarning
+
+// decode decodes the JSON response body r into v. If LenientDecode is
+// set, values that cannot be decoded are returned as warnings instead of
+// failing.
+func (s *Service) decode(r io.Reader, v interface{}) ([]*DecodeWarning, error) {
+	if s.LenientDecode {
+		return meplatoapi.DecodeLenient(r, v)
+	}
+	return nil, json.NewDecoder(r).Decode(v)
+}
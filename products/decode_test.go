@@ -0,0 +1,56 @@
+package products_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProductScrollLenientDecode(t *testing.T) {
+	service, ts, err := getService("products.scroll.invalid_field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	if _, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Do(context.Background()); err == nil {
+		t.Fatal("expected error without lenient decoding; got: nil")
+	}
+
+	service.LenientDecode = true
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected %d items; got: %d", 2, len(res.Items))
+	}
+	if p := res.Items[1]; p.Spn != "1001" || p.Name != "Produkt 1001" || p.Price != 0 {
+		t.Errorf("expected product 1001 without price; got: %+v", p)
+	}
+	if len(res.Warnings) != 1 {
+		t.Fatalf("expected %d warning; got: %v", 1, res.Warnings)
+	}
+	if got, want := res.Warnings[0].Field, "items[1].price"; got != want {
+		t.Errorf("expected warning for field %q; got: %q", want, got)
+	}
+}
+
+func TestProductGetLenientDecode(t *testing.T) {
+	service, ts, err := getService("products.get.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.LenientDecode = true
+
+	p, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Spn == "" {
+		t.Error("expected product to be decoded")
+	}
+	if len(p.Warnings) != 0 {
+		t.Errorf("expected no warnings; got: %v", p.Warnings)
+	}
+}
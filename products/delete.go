@@ -0,0 +1,29 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"net/http"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// deletedOnRetry reports whether res, a failed response to a delete
+// request, means that the product is gone anyway. This is the case if
+// the request has been retried and the server responds with 404: An
+// earlier attempt has deleted the product but its response got lost,
+// e.g. due to a gateway timeout. A 404 on the first attempt is still
+// reported as an error, as the product didn't exist in the first place.
+func deletedOnRetry(res *http.Response) bool {
+	return res.StatusCode == http.StatusNotFound && meplatoapi.Retried(res)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"sort"
+)
+
+// AreaDiff lists the products that differ between the work and the live
+// area of a catalog, i.e. the products that a publish will change. All
+// lists are sorted by SPN.
+type AreaDiff struct {
+	// Added are the SPNs of products that exist in work but not in live.
+	Added []string
+	// Removed are the SPNs of products that exist in live but not in work.
+	Removed []string
+	// Changed are the SPNs of products that exist in both areas but
+	// differ in at least one writable field.
+	Changed []string
+}
+
+// Empty reports whether there are no differences between the areas.
+func (d *AreaDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffAreas scrolls through the work and the live area of the catalog
+// with the given PIN and compares them, e.g. to verify what a publish
+// will change. Products are compared by their writable fields only, so
+// read-only fields like ID, Updated, or SelfLink, which always differ
+// between the areas, are ignored.
+//
+// The comparison is done locally and does not rely on differential
+// downloads on the server. It needs a checksum per product of the live
+// area in memory and scrolls through both areas completely, which may
+// take a while for big catalogs.
+func DiffAreas(ctx context.Context, service *Service, pin string) (*AreaDiff, error) {
+	live := make(map[string]string)
+	err := scrollChecksums(ctx, service, pin, "live", func(spn, sum string) {
+		live[spn] = sum
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	diff := new(AreaDiff)
+	err = scrollChecksums(ctx, service, pin, "work", func(spn, sum string) {
+		liveSum, found := live[spn]
+		switch {
+		case !found:
+			diff.Added = append(diff.Added, spn)
+		case liveSum != sum:
+			diff.Changed = append(diff.Changed, spn)
+		}
+		delete(live, spn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for spn := range live {
+		diff.Removed = append(diff.Removed, spn)
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// scrollChecksums scrolls through all products in the given area of a
+// catalog and calls fn with the SPN and the checksum of the writable
+// fields of every product.
+func scrollChecksums(ctx context.Context, service *Service, pin, area string, fn func(spn, sum string)) error {
+	it := service.Scroll().PIN(pin).Area(area).Iterator()
+	for {
+		items, ok, err := it.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		for _, p := range items {
+			if p == nil {
+				continue
+			}
+			u := new(UpsertProduct)
+			if err := convertProduct(p, u); err != nil {
+				return err
+			}
+			fn(p.Spn, Checksum([]*UpsertProduct{u}))
+		}
+	}
+}
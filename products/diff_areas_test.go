@@ -0,0 +1,69 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestDiffAreas(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if strings.Contains(r.URL.Path, "/live/") {
+			return "products.scroll.diff_areas.live"
+		}
+		return "products.scroll.diff_areas.work"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	diff, err := products.DiffAreas(context.Background(), service, "AD8CCDD5F9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1002"}; !reflect.DeepEqual(diff.Added, want) {
+		t.Errorf("expected added %v; got: %v", want, diff.Added)
+	}
+	if want := []string{"0999"}; !reflect.DeepEqual(diff.Removed, want) {
+		t.Errorf("expected removed %v; got: %v", want, diff.Removed)
+	}
+	if want := []string{"1001"}; !reflect.DeepEqual(diff.Changed, want) {
+		t.Errorf("expected changed %v; got: %v", want, diff.Changed)
+	}
+	if diff.Empty() {
+		t.Error("expected diff to not be empty")
+	}
+}
+
+func TestDiffAreasSame(t *testing.T) {
+	service, ts, err := getService("products.scroll.diff_areas.work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	diff, err := products.DiffAreas(context.Background(), service, "AD8CCDD5F9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no differences; got: %+v", diff)
+	}
+}
+
+func TestDiffAreasError(t *testing.T) {
+	service, ts, err := getService("products.search.unauthorized")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	if _, err := products.DiffAreas(context.Background(), service, "AD8CCDD5F9"); err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+}
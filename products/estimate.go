@@ -0,0 +1,45 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+// EstimateScrollRequests returns the number of requests that scrolling
+// through totalItems products takes when the server returns pageSize
+// products per page. Besides the pages with products, a scroll takes one
+// request to open the scroll, which returns no products, and one request
+// for the last, empty page. If pageSize is less than or equal to zero,
+// it returns 0.
+func EstimateScrollRequests(totalItems, pageSize int64) int64 {
+	if pageSize <= 0 {
+		return 0
+	}
+	return pages(totalItems, pageSize) + 2
+}
+
+// EstimateUploadRequests returns the number of requests that uploading
+// rows products takes. The API has no batch endpoint, so every product
+// takes one request, e.g. with Create, Update, Upsert, or Delete.
+func EstimateUploadRequests(rows int64) int64 {
+	if rows <= 0 {
+		return 0
+	}
+	return rows
+}
+
+// pages returns the number of pages of size n that are required for
+// total items.
+func pages(total, n int64) int64 {
+	if total <= 0 {
+		return 0
+	}
+	return (total + n - 1) / n
+}
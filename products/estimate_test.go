@@ -0,0 +1,41 @@
+package products_test
+
+import (
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestEstimateScrollRequests(t *testing.T) {
+	tests := []struct {
+		totalItems, pageSize, want int64
+	}{
+		{0, 100, 2},
+		{1, 100, 3},
+		{100, 100, 3},
+		{101, 100, 4},
+		{98621, 1800, 57},
+		{100, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := products.EstimateScrollRequests(tt.totalItems, tt.pageSize); got != tt.want {
+			t.Errorf("EstimateScrollRequests(%d, %d): expected %d; got: %d", tt.totalItems, tt.pageSize, tt.want, got)
+		}
+	}
+}
+
+func TestEstimateUploadRequests(t *testing.T) {
+	tests := []struct {
+		rows, want int64
+	}{
+		{0, 0},
+		{1, 1},
+		{250, 250},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := products.EstimateUploadRequests(tt.rows); got != tt.want {
+			t.Errorf("EstimateUploadRequests(%d): expected %d; got: %d", tt.rows, tt.want, got)
+		}
+	}
+}
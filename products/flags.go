@@ -0,0 +1,120 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+// The setters below set the flags of the product to update without the
+// need to take the address of a variable, e.g.
+//
+//	service.Update().PIN(pin).Area("work").Spn(spn).SetVisible(false).Do(ctx)
+//
+// Flags set this way are applied to a copy of the product passed to
+// Product in Do, regardless of the order of the calls, and override the
+// flags of that product.
+
+// setFlag records a flag to set on the product to update.
+func (s *UpdateService) setFlag(set func(p *UpdateProduct)) *UpdateService {
+	s.flags = append(s.flags, set)
+	return s
+}
+
+// applyFlags returns a copy of product with the flags set by the setters
+// applied. It returns product if no flags have been set.
+func (s *UpdateService) applyFlags(product *UpdateProduct) *UpdateProduct {
+	if len(s.flags) == 0 {
+		return product
+	}
+	ret := new(UpdateProduct)
+	if product != nil {
+		*ret = *product
+	}
+	for _, set := range s.flags {
+		set(ret)
+	}
+	return ret
+}
+
+// SetAutoConfigure sets the AutoConfigure flag of the product to update,
+// i.e. whether the product can be configured automatically.
+func (s *UpdateService) SetAutoConfigure(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.AutoConfigure = &v })
+}
+
+// SetCatalogManaged sets the CatalogManaged flag of the product to
+// update, i.e. whether the product is configurable (catalog managed in
+// OCI parlance).
+func (s *UpdateService) SetCatalogManaged(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.CatalogManaged = &v })
+}
+
+// SetExcluded sets the Excluded flag of the product to update, i.e.
+// whether the product is excluded from the catalog and not published into
+// the live area.
+func (s *UpdateService) SetExcluded(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.Excluded = &v })
+}
+
+// SetIncomplete sets the Incomplete flag of the product to update, i.e.
+// whether the product is incomplete.
+func (s *UpdateService) SetIncomplete(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.Incomplete = &v })
+}
+
+// SetIsPassword sets the IsPassword flag of the product to update, i.e.
+// whether the product is used to purchase a password.
+func (s *UpdateService) SetIsPassword(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.IsPassword = &v })
+}
+
+// SetKeepPrice sets the KeepPrice flag of the product to update, i.e.
+// whether the price of the product is kept instead of being calculated by
+// the catalog.
+func (s *UpdateService) SetKeepPrice(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.KeepPrice = &v })
+}
+
+// SetNeedsGoodsReceipt sets the NeedsGoodsReceipt flag of the product to
+// update, i.e. whether the product requires a goods receipt process.
+func (s *UpdateService) SetNeedsGoodsReceipt(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.NeedsGoodsReceipt = &v })
+}
+
+// SetOrderable sets the Orderable flag of the product to update, i.e.
+// whether the product is orderable by the end-user.
+func (s *UpdateService) SetOrderable(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.Orderable = &v })
+}
+
+// SetRateable sets the Rateable flag of the product to update, i.e.
+// whether the product can be rated by end-users.
+func (s *UpdateService) SetRateable(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.Rateable = &v })
+}
+
+// SetRateableOnlyIfOrdered sets the RateableOnlyIfOrdered flag of the
+// product to update, i.e. whether the product can be rated only after
+// being ordered.
+func (s *UpdateService) SetRateableOnlyIfOrdered(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.RateableOnlyIfOrdered = &v })
+}
+
+// SetService sets the Service flag of the product to update, i.e. whether
+// the product is a service (true) or a good (false).
+func (s *UpdateService) SetService(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.Service = &v })
+}
+
+// SetVisible sets the Visible flag of the product to update, i.e. whether
+// the product is visible to the end-user.
+func (s *UpdateService) SetVisible(v bool) *UpdateService {
+	return s.setFlag(func(p *UpdateProduct) { p.Visible = &v })
+}
@@ -0,0 +1,63 @@
+package products_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductUpdateSetFlags(t *testing.T) {
+	var sent map[string]interface{}
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		sent = nil
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+		}
+		return "products.update.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	name, visible := "Produkt 1000", true
+	p := &products.UpdateProduct{Name: &name, Visible: &visible}
+	_, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("1000").
+		SetVisible(false).
+		SetOrderable(true).
+		SetKeepPrice(false).
+		Product(p).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":      "Produkt 1000",
+		"visible":   false,
+		"orderable": true,
+		"keepPrice": false,
+	}
+	if len(sent) != len(want) {
+		t.Fatalf("expected %v to be sent; got: %v", want, sent)
+	}
+	for k, v := range want {
+		if sent[k] != v {
+			t.Errorf("expected %s to be %v; got: %v", k, v, sent[k])
+		}
+	}
+	if !*p.Visible {
+		t.Error("expected the product passed to Product to be left unchanged")
+	}
+
+	// Flags can be set without a product
+	_, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("1000").SetExcluded(true).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent["excluded"] != true {
+		t.Fatalf("expected only excluded to be sent; got: %v", sent)
+	}
+}
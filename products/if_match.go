@@ -0,0 +1,68 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// ErrPreconditionFailed is returned by ReplaceService if the product has
+// changed since it was read, i.e. its entity tag no longer matches the one
+// passed to IfMatch. Use errors.Is to test for it; the error also unwraps
+// to the *meplatoapi.Error returned by the server.
+var ErrPreconditionFailed = errors.New("products: precondition failed")
+
+// preconditionFailedError wraps a 412 Precondition Failed response.
+type preconditionFailedError struct {
+	err error
+}
+
+func (e *preconditionFailedError) Error() string {
+	return ErrPreconditionFailed.Error() + ": " + e.err.Error()
+}
+
+func (e *preconditionFailedError) Is(target error) bool {
+	return target == ErrPreconditionFailed
+}
+
+func (e *preconditionFailedError) Unwrap() error {
+	return e.err
+}
+
+// IfMatch sends the entity tag of the product, e.g. Product.ETag as
+// returned by Get, in the If-Match header. The server then rejects the
+// replace if the product has changed in the meantime, and Do returns an
+// error that matches ErrPreconditionFailed. Use it for optimistic
+// concurrency, i.e. to read, modify, and replace a product without
+// overwriting changes of other processes.
+func (s *ReplaceService) IfMatch(etag string) *ReplaceService {
+	if etag == "" {
+		delete(s.hdr_, "If-Match")
+	} else {
+		s.hdr_["If-Match"] = etag
+	}
+	return s
+}
+
+// preconditionFailed returns an error that matches ErrPreconditionFailed
+// if err is a 412 Precondition Failed response, and err otherwise.
+func preconditionFailed(err error) error {
+	var e *meplatoapi.Error
+	if errors.As(err, &e) && e.Code == http.StatusPreconditionFailed {
+		return &preconditionFailedError{err: err}
+	}
+	return err
+}
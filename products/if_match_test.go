@@ -0,0 +1,67 @@
+package products_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductReplaceIfMatch(t *testing.T) {
+	var ifMatch []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.Method == "GET" {
+			return "products.get.etag"
+		}
+		ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+		if r.Header.Get("If-Match") != `"a1b2c3"` {
+			return "products.replace.precondition_failed"
+		}
+		return "products.replace.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"a1b2c3"`; p.ETag != want {
+		t.Fatalf("expected ETag %s; got: %q", want, p.ETag)
+	}
+
+	replace := &products.ReplaceProduct{Name: "Produkt 1000 (NEU!)", Price: 2.50, OrderUnit: "PK"}
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Product(replace).IfMatch(p.ETag).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Product(replace).IfMatch(`"outdated"`).Do(context.Background())
+	if !errors.Is(err, products.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed; got: %v", err)
+	}
+	var apiErr *meplatoapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected error to unwrap to status %d; got: %v", http.StatusPreconditionFailed, err)
+	}
+
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Product(replace).IfMatch(`"outdated"`).IfMatch("").Do(context.Background())
+	if !errors.Is(err, products.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed; got: %v", err)
+	}
+
+	want := []string{`"a1b2c3"`, `"outdated"`, ""}
+	if len(ifMatch) != len(want) {
+		t.Fatalf("expected %d replace requests; got: %d", len(want), len(ifMatch))
+	}
+	for i := range want {
+		if ifMatch[i] != want[i] {
+			t.Errorf("#%d: expected If-Match %q; got: %q", i, want[i], ifMatch[i])
+		}
+	}
+}
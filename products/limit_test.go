@@ -0,0 +1,32 @@
+package products_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+)
+
+func TestProductMaxResponseBytes(t *testing.T) {
+	service, ts, err := getService("products.get.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	service.MaxResponseBytes = 16
+	_, err = service.Get().PIN("AD8CCDD5F9").Area("work").Spn("1000").Do(context.Background())
+	if !errors.Is(err, store2.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge; got: %v", err)
+	}
+
+	service.MaxResponseBytes = 1 << 20
+	p, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("1000").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil {
+		t.Fatal("expected product; got: nil")
+	}
+}
@@ -0,0 +1,44 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProductMaxConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var current, max int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		mu.Lock()
+		current++
+		if current > max {
+			max = current
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		current--
+		mu.Unlock()
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.SetMaxConcurrency(2)
+
+	spns := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	_, err = service.BatchGet().PIN("AD8CCDD5F9").Area("work").Spns(spns).Workers(len(spns)).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max > 2 {
+		t.Fatalf("expected at most %d concurrent requests; got: %d", 2, max)
+	}
+	if n := service.InFlight(); n != 0 {
+		t.Fatalf("expected no requests in flight; got: %d", n)
+	}
+}
@@ -0,0 +1,54 @@
+package products_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductSearchLinkHeader(t *testing.T) {
+	service, ts, err := getService("products.search.link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Search().PIN("AD8CCDD5F9").Area("work").Skip(20).Take(20).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := res.NextPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (products.PageRef{Skip: 40, Take: 20}); next == nil || *next != want {
+		t.Fatalf("expected next page %+v from Link header; got: %+v", want, next)
+	}
+	prev, err := res.PreviousPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (products.PageRef{Skip: 0, Take: 20}); prev == nil || *prev != want {
+		t.Fatalf("expected previous page %+v from Link header; got: %+v", want, prev)
+	}
+}
+
+func TestProductScrollLinkHeader(t *testing.T) {
+	service, ts, err := getService("products.scroll.link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "c2NhbjsyOzc1OQ=="; res.PageToken != want {
+		t.Fatalf("expected page token %q from Link header; got: %q", want, res.PageToken)
+	}
+	if res.NextLink == "" {
+		t.Fatal("expected next link from Link header; got: empty")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+)
+
+// listEdit adds values to or removes values from a list of a product.
+type listEdit struct {
+	field  string
+	add    bool
+	values []string
+}
+
+// AddKeywords adds keywords to the keywords of the product, skipping
+// those it already has.
+//
+// The API cannot edit a list partially. Instead, Do fetches the current
+// list from the server, unless the product to update already sets it,
+// applies the edits, and sends the complete list. Changes made by others
+// in between are overwritten.
+func (s *UpdateService) AddKeywords(keywords ...string) *UpdateService {
+	s.edits = append(s.edits, listEdit{field: "keywords", add: true, values: keywords})
+	return s
+}
+
+// RemoveKeywords removes keywords from the keywords of the product. The
+// list is updated like with AddKeywords.
+func (s *UpdateService) RemoveKeywords(keywords ...string) *UpdateService {
+	s.edits = append(s.edits, listEdit{field: "keywords", values: keywords})
+	return s
+}
+
+// AddCategories adds categories to the categories of the product,
+// skipping those it already has. The list is updated like with
+// AddKeywords.
+func (s *UpdateService) AddCategories(categories ...string) *UpdateService {
+	s.edits = append(s.edits, listEdit{field: "categories", add: true, values: categories})
+	return s
+}
+
+// RemoveCategories removes categories from the categories of the product.
+// The list is updated like with AddKeywords.
+func (s *UpdateService) RemoveCategories(categories ...string) *UpdateService {
+	s.edits = append(s.edits, listEdit{field: "categories", values: categories})
+	return s
+}
+
+// applyEdits returns a copy of the product to update with the edits of
+// AddKeywords, RemoveKeywords, AddCategories, and RemoveCategories
+// applied, along with the JSON names of the lists that became empty. The
+// product is fetched at most once, and only if a list to edit is not set
+// in the product to update.
+func (s *UpdateService) applyEdits(ctx context.Context) (*UpdateProduct, []string, error) {
+	if len(s.edits) == 0 {
+		return s.product, nil, nil
+	}
+	product := new(UpdateProduct)
+	if s.product != nil {
+		*product = *s.product
+	}
+	lists := map[string]*[]string{
+		"keywords":   &product.Keywords,
+		"categories": &product.Categories,
+	}
+
+	var current *Product
+	for _, edit := range s.edits {
+		list := lists[edit.field]
+		if *list == nil && current == nil {
+			// Only pass on the credentials: other headers, e.g. Prefer
+			// set by ReturnMinimal, are meant for the update
+			get := s.s.Get().PIN(s.pin).Area(s.area).Spn(s.spn)
+			if v, ok := s.hdr_["Authorization"]; ok {
+				get.hdr_["Authorization"] = v
+			}
+			var err error
+			if current, err = get.Do(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+		if *list == nil {
+			switch edit.field {
+			case "keywords":
+				*list = append([]string{}, current.Keywords...)
+			case "categories":
+				*list = append([]string{}, current.Categories...)
+			}
+		}
+		if edit.add {
+			*list = addValues(*list, edit.values)
+		} else {
+			*list = removeValues(*list, edit.values)
+		}
+	}
+
+	var empty []string
+	for _, field := range []string{"keywords", "categories"} {
+		if list := lists[field]; *list != nil && len(*list) == 0 {
+			empty = append(empty, field)
+		}
+	}
+	return product, empty, nil
+}
+
+// addValues appends the values to list that it does not contain yet.
+func addValues(list, values []string) []string {
+	for _, v := range values {
+		found := false
+		for _, x := range list {
+			if x == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// removeValues returns list without the values.
+func removeValues(list, values []string) []string {
+	ret := make([]string, 0, len(list))
+	for _, x := range list {
+		keep := true
+		for _, v := range values {
+			if x == v {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			ret = append(ret, x)
+		}
+	}
+	return ret
+}
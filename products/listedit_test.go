@@ -0,0 +1,117 @@
+package products_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductUpdateListEdits(t *testing.T) {
+	var gets int
+	var body map[string]interface{}
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.Method == "GET" {
+			gets++
+			return "products.get.lists"
+		}
+		body = nil
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		return "products.update.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx := context.Background()
+	name := "MacBook Air"
+	_, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").
+		Product(&products.UpdateProduct{Name: &name}).
+		AddKeywords("notebook", "apple").
+		RemoveKeywords("laptop").
+		AddCategories("computers").
+		Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gets != 1 {
+		t.Fatalf("expected %d get; got: %d", 1, gets)
+	}
+	if want := []interface{}{"apple", "notebook"}; !reflect.DeepEqual(body["keywords"], want) {
+		t.Errorf("expected keywords %v; got: %v", want, body["keywords"])
+	}
+	if want := []interface{}{"notebooks", "computers"}; !reflect.DeepEqual(body["categories"], want) {
+		t.Errorf("expected categories %v; got: %v", want, body["categories"])
+	}
+	if body["name"] != name {
+		t.Errorf("expected name %q; got: %v", name, body["name"])
+	}
+
+	// Removing the last category sends an empty list
+	gets = 0
+	_, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").
+		RemoveCategories("notebooks").
+		Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, found := body["categories"]; !found || len(v.([]interface{})) != 0 {
+		t.Errorf("expected empty categories; got: %v", v)
+	}
+	if _, found := body["keywords"]; found {
+		t.Errorf("expected keywords to be left unchanged; got: %v", body["keywords"])
+	}
+
+	// Lists set in the product are edited without fetching the product
+	gets = 0
+	_, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").
+		Product(&products.UpdateProduct{Keywords: []string{"a", "b"}}).
+		RemoveKeywords("a").
+		Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gets != 0 {
+		t.Fatalf("expected no get; got: %d", gets)
+	}
+	if want := []interface{}{"b"}; !reflect.DeepEqual(body["keywords"], want) {
+		t.Errorf("expected keywords %v; got: %v", want, body["keywords"])
+	}
+}
+
+func TestProductUpdateListEditsReturnMinimal(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.Header.Get("Authorization") == "" {
+			t.Errorf("%s: expected Authorization header", r.Method)
+		}
+		if r.Method == "GET" {
+			if v := r.Header.Get("Prefer"); v != "" {
+				t.Errorf("expected no Prefer header when fetching the product; got: %q", v)
+			}
+			return "products.get.lists"
+		}
+		if v := r.Header.Get("Prefer"); v != "return=minimal" {
+			t.Errorf("expected Prefer header %q on update; got: %q", "return=minimal", v)
+		}
+		return "products.update.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	_, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").
+		WithAuth("user", "secret").
+		ReturnMinimal().
+		AddKeywords("notebook").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,108 @@
+package products_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductMiddleware(t *testing.T) {
+	defer func(d time.Duration) { meplatoapi.RetryDelay = d }(meplatoapi.RetryDelay)
+	meplatoapi.RetryDelay = time.Millisecond
+
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		if got := r.Header.Get("X-Request-Id"); got != "42" {
+			t.Errorf("expected X-Request-Id header %q; got: %q", "42", got)
+		}
+		if requests == 1 {
+			return "products.create.unavailable"
+		}
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.MaxRetries = 1
+
+	var calls []string
+	var size int
+	service.Use(func(next products.RoundTripFunc) products.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "outer")
+			req.Header.Set("X-Request-Id", "42")
+			return next(req)
+		}
+	}, func(next products.RoundTripFunc) products.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "inner")
+			res, err := next(req)
+			if err != nil {
+				return nil, err
+			}
+			data, err := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			size = len(data)
+			res.Body = ioutil.NopCloser(bytes.NewReader(data))
+			return res, nil
+		}
+	})
+
+	product, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product == nil || product.Spn != "50763599" {
+		t.Fatalf("expected product %q; got: %v", "50763599", product)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests; got: %d", requests)
+	}
+	if len(calls) != 2 || calls[0] != "outer" || calls[1] != "inner" {
+		t.Errorf("expected calls [outer inner]; got: %v", calls)
+	}
+	if size == 0 {
+		t.Errorf("expected middleware to read the response body")
+	}
+}
+
+func TestProductMiddlewareShortCircuit(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		t.Errorf("expected no request to be sent; got: %s %s", r.Method, r.URL)
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	service.Use(func(next products.RoundTripFunc) products.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"spn":"cached"}`)),
+				Request:    req,
+			}, nil
+		}
+	})
+
+	product, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product == nil || product.Spn != "cached" {
+		t.Fatalf("expected cached product; got: %v", product)
+	}
+}
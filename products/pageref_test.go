@@ -0,0 +1,49 @@
+package products_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductSearchPageRef(t *testing.T) {
+	service, ts, err := getService("products.search.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Search().PIN("PIN").Area("work").Skip(0).Take(30).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := res.NextPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == nil {
+		t.Fatal("expected next page; got: nil")
+	}
+	if want := (products.PageRef{Skip: 30, Take: 30}); *next != want {
+		t.Fatalf("expected next page %+v; got: %+v", want, *next)
+	}
+	prev, err := res.PreviousPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev != nil {
+		t.Fatalf("expected no previous page; got: %+v", *prev)
+	}
+
+	if _, err := service.Search().PIN("PIN").Area("work").Page(*next).Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProductSearchPageRefInvalid(t *testing.T) {
+	res := &products.SearchResponse{NextLink: "https://store.meplato.com/api/v2/catalogs/PIN/work/products?skip=abc"}
+	if _, err := res.NextPage(); err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+// preferMinimal is the value of the Prefer header that asks the server to
+// not return a representation of the written product.
+const preferMinimal = "return=minimal"
+
+// ReturnMinimal asks the server to respond without a body by sending
+// "Prefer: return=minimal", and skips decoding the response. Use it for
+// bulk writes. The returned response has no Kind and Link; Location is
+// still set if the server returns it.
+func (s *CreateService) ReturnMinimal() *CreateService {
+	s.hdr_["Prefer"] = preferMinimal
+	s.minimal = true
+	return s
+}
+
+// ReturnMinimal asks the server to respond without a body by sending
+// "Prefer: return=minimal", and skips decoding the response. Use it for
+// bulk writes. The returned response has no Kind and Link.
+func (s *UpdateService) ReturnMinimal() *UpdateService {
+	s.hdr_["Prefer"] = preferMinimal
+	s.minimal = true
+	return s
+}
+
+// ReturnMinimal asks the server to respond without a body by sending
+// "Prefer: return=minimal", and skips decoding the response. Use it for
+// bulk writes. The returned response has no Kind and Link.
+func (s *ReplaceService) ReturnMinimal() *ReplaceService {
+	s.hdr_["Prefer"] = preferMinimal
+	s.minimal = true
+	return s
+}
+
+// ReturnMinimal asks the server to respond without a body by sending
+// "Prefer: return=minimal", and skips decoding the response. Use it for
+// bulk writes. The returned response has no Kind and Link; Location is
+// still set if the server returns it.
+func (s *UpsertService) ReturnMinimal() *UpsertService {
+	s.hdr_["Prefer"] = preferMinimal
+	s.minimal = true
+	return s
+}
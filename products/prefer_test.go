@@ -0,0 +1,53 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductWritesReturnMinimal(t *testing.T) {
+	var prefer []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		prefer = append(prefer, r.Header.Get("Prefer"))
+		return "products.write.minimal"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx := context.Background()
+	cres, err := service.Create().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleCreateProduct()).ReturnMinimal().Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cres.Location == "" {
+		t.Error("expected Location to be set")
+	}
+	name := "Changed"
+	if _, err := service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(&products.UpdateProduct{Name: &name}).ReturnMinimal().Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(&products.ReplaceProduct{Name: name, Price: 1, OrderUnit: "PCE"}).ReturnMinimal().Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleUpsertProduct()).ReturnMinimal().Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(prefer) != 4 {
+		t.Fatalf("expected %d requests; got: %d", 4, len(prefer))
+	}
+	for i, p := range prefer {
+		if p != "return=minimal" {
+			t.Errorf("#%d: expected Prefer %q; got: %q", i, "return=minimal", p)
+		}
+	}
+
+	// Without ReturnMinimal, the empty body cannot be decoded
+	if _, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleUpsertProduct()).Do(ctx); err == nil {
+		t.Fatal("expected decoding error; got: nil")
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
 	"strings"
@@ -60,7 +61,51 @@ type Service struct {
 	BaseURL  string
 	User     string
 	Password string
-}
+	// MaxRetries is the number of times a request is retried on network
+	// errors and transient server errors like 503 (default 0).
+	MaxRetries int
+	// Debug logs every request and response, including the indented JSON
+	// bodies, to Logger. The Authorization header is redacted.
+	Debug bool
+	// Logger is used in debug mode. If nil, the standard logger is used.
+	Logger Logger
+	// MaxResponseBytes limits the size of response bodies. Reading a larger
+	// body fails with an error that matches store2.ErrResponseTooLarge
+	// (default 0, i.e. unlimited).
+	MaxResponseBytes int64
+	// Trace, if set, creates a ClientTrace for every request, e.g. to
+	// measure DNS, connect, TLS, and time-to-first-byte timings. See
+	// store2.TimingTrace for a ready-made implementation.
+	Trace func(req *http.Request) *httptrace.ClientTrace
+	// ContextHeaders maps context keys to the names of HTTP headers. If
+	// the context of a request has a value for a key, e.g. a tenant or
+	// correlation ID stored by middleware, it is sent in the mapped header
+	// without passing it to every operation. Values are formatted with
+	// fmt.Sprint. Headers set by an operation take precedence.
+	ContextHeaders map[interface{}]string
+	// ObserveAttempts, if set, is called after every request with the
+	// number of attempts it took, i.e. 1 if it was not retried, e.g. to
+	// record retries in your metrics and alert when the backend is flaky.
+	ObserveAttempts func(req *http.Request, attempts int)
+	// LenientDecode makes Get, Search, and Scroll tolerate values in the
+	// response that cannot be decoded into their fields, e.g. a string
+	// where a number is expected. Such fields are left empty and reported
+	// in the Warnings of the result instead of failing the call.
+	LenientDecode bool
+
+	limiter    meplatoapi.Limiter
+	middleware []Middleware
+}
+
+// Logger is used to log requests and responses in debug mode. It is
+// implemented by *log.Logger.
+type Logger = meplatoapi.Logger
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc = meplatoapi.RoundTripFunc
+
+// Middleware wraps the RoundTripFunc of a service. See Service.Use.
+type Middleware = meplatoapi.Middleware
 
 func New(client *http.Client) (*Service, error) {
 	if client == nil {
@@ -69,6 +114,108 @@ func New(client *http.Client) (*Service, error) {
 	return &Service{client: client, BaseURL: baseURL}, nil
 }
 
+// NewFromEnv creates a new Service with a default HTTP client. The base
+// URL, user, and password are read from the STORE2_URL, STORE2_USER, and
+// STORE2_PASSWORD environment variables. If STORE2_URL is not set, the
+// default base URL is used. Debug logging is enabled if STORE2_DEBUG is
+// set to true.
+func NewFromEnv() (*Service, error) {
+	s, err := New(meplatoapi.NewDefaultClient())
+	if err != nil {
+		return nil, err
+	}
+	baseURL, user, password := meplatoapi.FromEnv()
+	if baseURL != "" {
+		s.BaseURL = baseURL
+	}
+	s.User = user
+	s.Password = password
+	s.Debug = meplatoapi.DebugFromEnv()
+	return s, nil
+}
+
+// SetMaxConcurrency limits the number of concurrent requests across all
+// operations of the service to n. Requests beyond the limit block until a
+// request finishes or their context is done. If n is less than or equal
+// to zero, the number of requests is not limited (default).
+func (s *Service) SetMaxConcurrency(n int) {
+	s.limiter.SetMax(n)
+}
+
+// WithProxy sends all requests of the service through the given proxy,
+// e.g. http://proxy.example.com:3128, so that services in one process can
+// use different proxies. It replaces the proxy from the HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY environment variables for this service; if
+// proxy is nil, requests are sent directly. The HTTP client of the
+// service is copied, so other users of the client are not affected. It
+// returns an error if the proxy URL is invalid or the client has a custom
+// transport that is not an *http.Transport.
+func (s *Service) WithProxy(proxy *url.URL) error {
+	client, err := meplatoapi.WithProxy(s.client, proxy)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+// InFlight returns the number of requests of the service that are
+// currently in flight.
+func (s *Service) InFlight() int {
+	return s.limiter.InFlight()
+}
+
+// Use adds middleware that wraps every request of the service, e.g. to
+// log, measure, cache, or rewrite requests and responses. Middleware runs
+// in the order it was added, i.e. the first one sees the request first
+// and the response last. It sees the request once, however often it is
+// retried, and the response body is already limited to MaxResponseBytes.
+// Use must not be called concurrently with requests.
+func (s *Service) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// do adds the headers of ContextHeaders to req, attaches the trace
+// created by Trace, and sends it through the middleware added by Use.
+func (s *Service) do(req *http.Request) (*http.Response, error) {
+	meplatoapi.SetContextHeaders(req, s.ContextHeaders)
+	req = meplatoapi.WithTrace(req, s.Trace)
+	return meplatoapi.Chain(s.send, s.middleware)(req)
+}
+
+// send sends req, retrying it according to MaxRetries and respecting the
+// limit set by SetMaxConcurrency. It reports the number of attempts to
+// ObserveAttempts, limits the response body to MaxResponseBytes, and, in
+// debug mode, logs the request and the response.
+func (s *Service) send(req *http.Request) (*http.Response, error) {
+	if s.Debug {
+		if err := meplatoapi.LogRequest(s.Logger, req); err != nil {
+			return nil, err
+		}
+	}
+	var attempts int
+	if s.ObserveAttempts != nil {
+		req = meplatoapi.CountAttempts(req, &attempts)
+	}
+	res, err := s.limiter.Send(s.client, req, s.MaxRetries)
+	if s.ObserveAttempts != nil {
+		s.ObserveAttempts(req, attempts)
+	}
+	if err == nil {
+		if err := meplatoapi.LimitBody(res, s.MaxResponseBytes); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	if err == nil && s.Debug {
+		if err := meplatoapi.LogResponse(s.Logger, res); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	return res, err
+}
+
 func (s *Service) Create() *CreateService {
 	return NewCreateService(s)
 }
@@ -535,6 +682,18 @@ type CreateProductResponse struct {
 	Kind string `json:"kind,omitempty"`
 	// Link returns a URL to the representation of the newly created product.
 	Link string `json:"link,omitempty"`
+	// Warnings are non-fatal issues the server reported for the request,
+	// e.g. the use of a deprecated field. It is empty if there are none.
+	Warnings []string `json:"warnings,omitempty"`
+	// Location is the canonical URL of the product as returned in the
+	// Location header of the response. It is empty if the server did not
+	// return the header.
+	Location string `json:"-"`
+	// JobID is the ID of the background job that processes the request,
+	// if any. Use it with the jobs package, e.g. jobs.Service.WaitForState.
+	// It is taken from the jobId of the response or from a Location header
+	// that refers to a job.
+	JobID string `json:"jobId,omitempty"`
 }
 
 // CustField describes a generic name/value pair. Its purpose is to
@@ -792,6 +951,9 @@ type Product struct {
 	Description string `json:"description,omitempty"`
 	// Eclasses is a list of eCl@ss categories the product belongs to.
 	Eclasses []*Eclass `json:"eclasses,omitempty"`
+	// ETag is the entity tag of the product as returned by Get. Pass it to
+	// ReplaceService.IfMatch to prevent lost updates.
+	ETag string `json:"-"`
 	// ErpGroupSupplier: erpGroupSupplier is the material group of the product
 	// on the merchant-/supplier-side.
 	ErpGroupSupplier string `json:"erpGroupSupplier,omitempty"`
@@ -960,6 +1122,10 @@ type Product struct {
 	// Visible is a flag that indicates whether this product will be visible
 	// to the end-user when shopping.
 	Visible *bool `json:"visible,omitempty"`
+	// Warnings lists the values of the response that could not be decoded
+	// if Service.LenientDecode is set. It is only set by Get; see the
+	// Warnings of the response for products returned by Search or Scroll.
+	Warnings []*DecodeWarning `json:"-"`
 }
 
 // Reference describes a reference from one product to another product.
@@ -1369,6 +1535,9 @@ type ReplaceProductResponse struct {
 	Kind string `json:"kind,omitempty"`
 	// Link returns a URL to the representation of the replaced product.
 	Link string `json:"link,omitempty"`
+	// Warnings are non-fatal issues the server reported for the request,
+	// e.g. the use of a deprecated field. It is empty if there are none.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ScalePrice describes a price that is dependent on the ordered quantity.
@@ -1391,6 +1560,7 @@ type ScrollResponse struct {
 	// Kind is store#products/scroll for this kind of response.
 	Kind string `json:"kind,omitempty"`
 	// NextLink returns the URL to the next slice of products (if any).
+	// If the response body has none, it is taken from the Link header.
 	NextLink string `json:"nextLink,omitempty"`
 	// PageToken needs to be passed to get the next slice of products. It is
 	// blank if there are no more products. Instead of using pageToken for
@@ -1399,10 +1569,21 @@ type ScrollResponse struct {
 	// PreviousLink returns the URL of the previous slice of products (if
 	// any).
 	PreviousLink string `json:"previousLink,omitempty"`
+	// Processed is the number of products returned by Pages so far,
+	// including the products of this page. It is only set by Pages.
+	Processed int64 `json:"-"`
+	// Restarted indicates that the page token had expired and the scroll
+	// was started over at the first page. See ScrollService.RestartOnExpiry.
+	Restarted bool `json:"-"`
 	// SelfLink returns the URL to this page.
 	SelfLink string `json:"selfLink,omitempty"`
-	// TotalItems describes the total number of products found.
+	// TotalItems describes the total number of products found. If the
+	// server returns it on the first page only, Pages sets it on the
+	// subsequent pages as well.
 	TotalItems int64 `json:"totalItems,omitempty"`
+	// Warnings lists the values of the response that could not be decoded
+	// if Service.LenientDecode is set, e.g. items[3].price.
+	Warnings []*DecodeWarning `json:"-"`
 }
 
 // SearchResponse is a partial listing of products.
@@ -1412,6 +1593,7 @@ type SearchResponse struct {
 	// Kind is store#products/search for this kind of response.
 	Kind string `json:"kind,omitempty"`
 	// NextLink returns the URL to the next slice of products (if any).
+	// If the response body has none, it is taken from the Link header.
 	NextLink string `json:"nextLink,omitempty"`
 	// PreviousLink returns the URL of the previous slice of products (if
 	// any).
@@ -1420,6 +1602,9 @@ type SearchResponse struct {
 	SelfLink string `json:"selfLink,omitempty"`
 	// TotalItems describes the total number of products found.
 	TotalItems int64 `json:"totalItems,omitempty"`
+	// Warnings lists the values of the response that could not be decoded
+	// if Service.LenientDecode is set, e.g. items[3].price.
+	Warnings []*DecodeWarning `json:"-"`
 }
 
 // Unspsc is used to tie a product to a UNSPSC schema.
@@ -1825,6 +2010,9 @@ type UpdateProductResponse struct {
 	Kind string `json:"kind,omitempty"`
 	// Link returns a URL to the representation of the updated product.
 	Link string `json:"link,omitempty"`
+	// Warnings are non-fatal issues the server reported for the request,
+	// e.g. the use of a deprecated field. It is empty if there are none.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // UpsertProduct holds the properties of the product to create or update.
@@ -2224,16 +2412,34 @@ type UpsertProductResponse struct {
 	// Link returns a URL to the representation of the created or updated
 	// product.
 	Link string `json:"link,omitempty"`
+	// Warnings are non-fatal issues the server reported for the request,
+	// e.g. the use of a deprecated field. It is empty if there are none.
+	Warnings []string `json:"warnings,omitempty"`
+	// Location is the canonical URL of the product as returned in the
+	// Location header of the response. It is empty if the server did not
+	// return the header.
+	Location string `json:"-"`
+	// JobID is the ID of the background job that processes the request,
+	// if any. Use it with the jobs package, e.g. jobs.Service.WaitForState.
+	// It is taken from the jobId of the response or from a Location header
+	// that refers to a job.
+	JobID string `json:"jobId,omitempty"`
 }
 
 // Create a new product in the given catalog and area.
 type CreateService struct {
-	s       *Service
-	opt_    map[string]interface{}
-	hdr_    map[string]interface{}
-	pin     string
-	area    string
-	product *CreateProduct
+	s                   *Service
+	opt_                map[string]interface{}
+	hdr_                map[string]interface{}
+	pin                 string
+	area                string
+	product             *CreateProduct
+	validate            bool
+	validateUnits       bool
+	validateScalePrices bool
+	stripHTML           bool
+	target              string
+	minimal             bool
 }
 
 // NewCreateService creates a new instance of CreateService.
@@ -2260,21 +2466,53 @@ func (s *CreateService) Product(product *CreateProduct) *CreateService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *CreateService) WithAuth(user, password string) *CreateService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *CreateService) Do(ctx context.Context) (*CreateProductResponse, error) {
+	product := s.product
+	if s.stripHTML && product != nil {
+		// Leave the product of the caller unchanged
+		p := *product
+		p.Description = StripHTML(p.Description)
+		product = &p
+	}
+	if s.validate {
+		if err := ValidateForTarget(product, s.target); err != nil {
+			return nil, err
+		}
+	}
+	if s.validateUnits {
+		if err := ValidateUnits(product); err != nil {
+			return nil, err
+		}
+	}
+	if s.validateScalePrices && product != nil {
+		if err := SortAndValidateScalePrices(product.ScalePrices); err != nil {
+			return nil, err
+		}
+	}
 	var body io.Reader
-	body, err := meplatoapi.ReadJSON(s.product)
+	body, err := meplatoapi.ReadJSON(product)
 	if err != nil {
 		return nil, err
 	}
 	params := make(map[string]interface{})
 	params["area"] = s.area
 	params["pin"] = s.pin
+	if err := meplatoapi.RequireParams(params, "pin", "area"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("POST", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2286,7 +2524,10 @@ func (s *CreateService) Do(ctx context.Context) (*CreateProductResponse, error)
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2295,9 +2536,13 @@ func (s *CreateService) Do(ctx context.Context) (*CreateProductResponse, error)
 		return nil, err
 	}
 	ret := new(CreateProductResponse)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
-		return nil, err
+	if !s.minimal {
+		if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+			return nil, err
+		}
 	}
+	ret.Location = res.Header.Get("Location")
+	ret.JobID = meplatoapi.JobID(ret.JobID, res)
 	return ret, nil
 }
 
@@ -2335,6 +2580,13 @@ func (s *DeleteService) Spn(spn string) *DeleteService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *DeleteService) WithAuth(user, password string) *DeleteService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *DeleteService) Do(ctx context.Context) error {
 	var body io.Reader
@@ -2342,11 +2594,14 @@ func (s *DeleteService) Do(ctx context.Context) error {
 	params["area"] = s.area
 	params["pin"] = s.pin
 	params["spn"] = s.spn
+	if err := meplatoapi.RequireParams(params, "pin", "area", "spn"); err != nil {
+		return err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/{spn}", params)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest("DELETE", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("DELETE", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return err
 	}
@@ -2358,12 +2613,18 @@ func (s *DeleteService) Do(ctx context.Context) error {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return err
 	}
 	defer meplatoapi.CloseBody(res)
 	if err := meplatoapi.CheckResponse(res); err != nil {
+		if deletedOnRetry(res) {
+			return nil
+		}
 		return err
 	}
 	return nil
@@ -2403,18 +2664,30 @@ func (s *GetService) Spn(spn string) *GetService {
 	return s
 }
 
-// Do executes the operation.
-func (s *GetService) Do(ctx context.Context) (*Product, error) {
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *GetService) WithAuth(user, password string) *GetService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// DoResponse executes the operation like Do, but returns the response
+// with its body unread. The caller must close the body. Responses with a
+// status code other than 2xx are returned as errors.
+func (s *GetService) DoResponse(ctx context.Context) (*http.Response, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["area"] = s.area
 	params["pin"] = s.pin
 	params["spn"] = s.spn
+	if err := meplatoapi.RequireParams(params, "pin", "area", "spn"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/{spn}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2426,18 +2699,32 @@ func (s *GetService) Do(ctx context.Context) (*Product, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer meplatoapi.CloseBody(res)
 	if err := meplatoapi.CheckResponse(res); err != nil {
+		meplatoapi.CloseBody(res)
+		return nil, err
+	}
+	return res, nil
+}
+
+// Do executes the operation.
+func (s *GetService) Do(ctx context.Context) (*Product, error) {
+	res, err := s.DoResponse(ctx)
+	if err != nil {
 		return nil, err
 	}
+	defer meplatoapi.CloseBody(res)
 	ret := new(Product)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+	if ret.Warnings, err = s.s.decode(res.Body, ret); err != nil {
 		return nil, err
 	}
+	ret.ETag = res.Header.Get("ETag")
 	return ret, nil
 }
 
@@ -2451,6 +2738,9 @@ type ReplaceService struct {
 	area    string
 	spn     string
 	product *ReplaceProduct
+	err     error
+	empty   []string
+	minimal bool
 }
 
 // NewReplaceService creates a new instance of ReplaceService.
@@ -2477,16 +2767,34 @@ func (s *ReplaceService) Product(product *ReplaceProduct) *ReplaceService {
 	return s
 }
 
+// IncludeEmpty lists collections of the product, by their JSON names
+// like "categories", that are sent even if they are empty. Use it to
+// clear these collections, as empty collections are omitted by default.
+func (s *ReplaceService) IncludeEmpty(fields ...string) *ReplaceService {
+	s.empty = append(s.empty, fields...)
+	return s
+}
+
 // SPN is the supplier part number of the product to replace.
 func (s *ReplaceService) Spn(spn string) *ReplaceService {
 	s.spn = spn
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *ReplaceService) WithAuth(user, password string) *ReplaceService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *ReplaceService) Do(ctx context.Context) (*ReplaceProductResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
 	var body io.Reader
-	body, err := meplatoapi.ReadJSON(s.product)
+	body, err := meplatoapi.ReadJSONIncludeEmpty(s.product, s.empty)
 	if err != nil {
 		return nil, err
 	}
@@ -2494,11 +2802,14 @@ func (s *ReplaceService) Do(ctx context.Context) (*ReplaceProductResponse, error
 	params["area"] = s.area
 	params["pin"] = s.pin
 	params["spn"] = s.spn
+	if err := meplatoapi.RequireParams(params, "pin", "area", "spn"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/{spn}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("PUT", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("PUT", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2510,17 +2821,22 @@ func (s *ReplaceService) Do(ctx context.Context) (*ReplaceProductResponse, error
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer meplatoapi.CloseBody(res)
 	if err := meplatoapi.CheckResponse(res); err != nil {
-		return nil, err
+		return nil, preconditionFailed(err)
 	}
 	ret := new(ReplaceProductResponse)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
-		return nil, err
+	if !s.minimal {
+		if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+			return nil, err
+		}
 	}
 	return ret, nil
 }
@@ -2529,11 +2845,14 @@ func (s *ReplaceService) Do(ctx context.Context) (*ReplaceProductResponse, error
 // through all products in a catalog, this is the most effective way to do
 // so. If you want to search for products, use the Search endpoint.
 type ScrollService struct {
-	s    *Service
-	opt_ map[string]interface{}
-	hdr_ map[string]interface{}
-	pin  string
-	area string
+	s        *Service
+	opt_     map[string]interface{}
+	hdr_     map[string]interface{}
+	pin      string
+	area     string
+	restart  bool
+	maxPages int
+	limit    int
 }
 
 // NewScrollService creates a new instance of ScrollService.
@@ -2559,6 +2878,19 @@ func (s *ScrollService) Mode(mode string) *ScrollService {
 	return s
 }
 
+// PageSize defines how many products to return per page. Larger pages
+// need fewer requests to scroll through a catalog. The server may return
+// fewer products per page than requested. If n is less than or equal to
+// zero, the server default is used.
+func (s *ScrollService) PageSize(n int) *ScrollService {
+	if n > 0 {
+		s.opt_["take"] = n
+	} else {
+		delete(s.opt_, "take")
+	}
+	return s
+}
+
 // PageToken must be passed in the 2nd and all consective requests to get
 // the next page of results. You do not need to pass the page token
 // manually. You should just follow the nextUrl link in the metadata to
@@ -2578,14 +2910,35 @@ func (s *ScrollService) PIN(pin string) *ScrollService {
 	return s
 }
 
+// RestartOnExpiry specifies whether Do starts over at the first page when
+// the page token has expired on the server. By default, Do returns
+// ErrScrollExpired instead. Restarting means that the caller will see
+// products again that were already returned by earlier pages; check
+// ScrollResponse.Restarted to detect this.
+func (s *ScrollService) RestartOnExpiry(restart bool) *ScrollService {
+	s.restart = restart
+	return s
+}
+
 // Version of the catalog to be retrieved
 func (s *ScrollService) Version(version int64) *ScrollService {
 	s.opt_["version"] = version
 	return s
 }
 
-// Do executes the operation.
-func (s *ScrollService) Do(ctx context.Context) (*ScrollResponse, error) {
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *ScrollService) WithAuth(user, password string) *ScrollService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// DoResponse executes the operation like Do, but returns the response
+// with its body unread, so that large pages can be decoded as a stream or
+// read in the content type requested with Accept. The caller must close
+// the body. Responses with a status code other than
+// 2xx are returned as errors. RestartOnExpiry only applies to Do.
+func (s *ScrollService) DoResponse(ctx context.Context) (*http.Response, error) {
 	var body io.Reader
 	params := make(map[string]interface{})
 	params["area"] = s.area
@@ -2596,14 +2949,20 @@ func (s *ScrollService) Do(ctx context.Context) (*ScrollResponse, error) {
 		params["pageToken"] = v
 	}
 	params["pin"] = s.pin
+	if v, ok := s.opt_["take"]; ok {
+		params["take"] = v
+	}
 	if v, ok := s.opt_["version"]; ok {
 		params["version"] = v
 	}
-	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/scroll{?pageToken,mode,version}", params)
+	if err := meplatoapi.RequireParams(params, "pin", "area"); err != nil {
+		return nil, err
+	}
+	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/scroll{?pageToken,mode,version,take}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2615,18 +2974,59 @@ func (s *ScrollService) Do(ctx context.Context) (*ScrollResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer meplatoapi.CloseBody(res)
 	if err := meplatoapi.CheckResponse(res); err != nil {
+		meplatoapi.CloseBody(res)
+		if v, ok := s.opt_["pageToken"]; ok && v != "" && isScrollExpired(err) {
+			return nil, &scrollExpiredError{err: err}
+		}
 		return nil, err
 	}
+	if err := s.checkContentType(res); err != nil {
+		meplatoapi.CloseBody(res)
+		return nil, err
+	}
+	return res, nil
+}
+
+// Do executes the operation.
+func (s *ScrollService) Do(ctx context.Context) (*ScrollResponse, error) {
+	if s.accept() != "" {
+		return nil, ErrNotJSON
+	}
+	res, err := s.DoResponse(ctx)
+	if err != nil {
+		if s.restart && errors.Is(err, ErrScrollExpired) {
+			delete(s.opt_, "pageToken")
+			ret, err := s.Do(ctx)
+			if ret != nil {
+				ret.Restarted = true
+			}
+			return ret, err
+		}
+		return nil, err
+	}
+	defer meplatoapi.CloseBody(res)
 	ret := new(ScrollResponse)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+	if ret.Warnings, err = s.s.decode(res.Body, ret); err != nil {
 		return nil, err
 	}
+	next, prev := meplatoapi.PageLinks(res)
+	if ret.NextLink == "" {
+		ret.NextLink = next
+	}
+	if ret.PreviousLink == "" {
+		ret.PreviousLink = prev
+	}
+	if ret.PageToken == "" && next != "" {
+		ret.PageToken = pageTokenFromLink(next)
+	}
 	return ret, nil
 }
 
@@ -2671,7 +3071,8 @@ func (s *SearchService) Skip(skip int64) *SearchService {
 	return s
 }
 
-// Sort order, e.g. name, spn, id or -created (default: score).
+// Sort order, e.g. name, spn, id or -created (default: score). Unknown
+// sort keys are rejected by Do; see ValidateSort and the SortBy constants.
 func (s *SearchService) Sort(sort string) *SearchService {
 	s.opt_["sort"] = sort
 	return s
@@ -2683,9 +3084,21 @@ func (s *SearchService) Take(take int64) *SearchService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *SearchService) WithAuth(user, password string) *SearchService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	var body io.Reader
+	if v, ok := s.opt_["sort"]; ok {
+		if err := ValidateSort(fmt.Sprint(v)); err != nil {
+			return nil, err
+		}
+	}
 	params := make(map[string]interface{})
 	params["area"] = s.area
 	params["pin"] = s.pin
@@ -2701,11 +3114,14 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if v, ok := s.opt_["take"]; ok {
 		params["take"] = v
 	}
+	if err := meplatoapi.RequireParams(params, "pin", "area"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products{?q,skip,take,sort}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2717,7 +3133,10 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2726,9 +3145,16 @@ func (s *SearchService) Do(ctx context.Context) (*SearchResponse, error) {
 		return nil, err
 	}
 	ret := new(SearchResponse)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+	if ret.Warnings, err = s.s.decode(res.Body, ret); err != nil {
 		return nil, err
 	}
+	next, prev := meplatoapi.PageLinks(res)
+	if ret.NextLink == "" {
+		ret.NextLink = next
+	}
+	if ret.PreviousLink == "" {
+		ret.PreviousLink = prev
+	}
 	return ret, nil
 }
 
@@ -2742,6 +3168,9 @@ type UpdateService struct {
 	area    string
 	spn     string
 	product *UpdateProduct
+	minimal bool
+	edits   []listEdit
+	flags   []func(*UpdateProduct)
 }
 
 // NewUpdateService creates a new instance of UpdateService.
@@ -2774,10 +3203,22 @@ func (s *UpdateService) Spn(spn string) *UpdateService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *UpdateService) WithAuth(user, password string) *UpdateService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *UpdateService) Do(ctx context.Context) (*UpdateProductResponse, error) {
 	var body io.Reader
-	body, err := meplatoapi.ReadJSON(s.product)
+	product, empty, err := s.applyEdits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	product = s.applyFlags(product)
+	body, err = meplatoapi.ReadJSONIncludeEmpty(product, empty)
 	if err != nil {
 		return nil, err
 	}
@@ -2785,11 +3226,14 @@ func (s *UpdateService) Do(ctx context.Context) (*UpdateProductResponse, error)
 	params["area"] = s.area
 	params["pin"] = s.pin
 	params["spn"] = s.spn
+	if err := meplatoapi.RequireParams(params, "pin", "area", "spn"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/{spn}", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("POST", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2801,7 +3245,10 @@ func (s *UpdateService) Do(ctx context.Context) (*UpdateProductResponse, error)
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2810,8 +3257,10 @@ func (s *UpdateService) Do(ctx context.Context) (*UpdateProductResponse, error)
 		return nil, err
 	}
 	ret := new(UpdateProductResponse)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
-		return nil, err
+	if !s.minimal {
+		if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+			return nil, err
+		}
 	}
 	return ret, nil
 }
@@ -2819,12 +3268,20 @@ func (s *UpdateService) Do(ctx context.Context) (*UpdateProductResponse, error)
 // Upsert a product in the given catalog and area. Upsert will create if
 // the product does not exist yet, otherwise it will update.
 type UpsertService struct {
-	s       *Service
-	opt_    map[string]interface{}
-	hdr_    map[string]interface{}
-	pin     string
-	area    string
-	product *UpsertProduct
+	s                   *Service
+	opt_                map[string]interface{}
+	hdr_                map[string]interface{}
+	pin                 string
+	area                string
+	product             *UpsertProduct
+	validate            bool
+	validateUnits       bool
+	validateScalePrices bool
+	stripHTML           bool
+	target              string
+	minimal             bool
+	mode                UpsertMode
+	err                 error
 }
 
 // NewUpsertService creates a new instance of UpsertService.
@@ -2851,21 +3308,59 @@ func (s *UpsertService) Product(product *UpsertProduct) *UpsertService {
 	return s
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *UpsertService) WithAuth(user, password string) *UpsertService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *UpsertService) Do(ctx context.Context) (*UpsertProductResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	product := s.product
+	if s.stripHTML && product != nil {
+		// Leave the product of the caller unchanged
+		p := *product
+		p.Description = StripHTML(p.Description)
+		product = &p
+	}
+	if s.validate {
+		if err := ValidateForTarget(product, s.target); err != nil {
+			return nil, err
+		}
+	}
+	if s.validateUnits {
+		if err := ValidateUnits(product); err != nil {
+			return nil, err
+		}
+	}
+	if s.validateScalePrices && product != nil {
+		if err := SortAndValidateScalePrices(product.ScalePrices); err != nil {
+			return nil, err
+		}
+	}
+	if s.mode != UpsertCreateOrUpdate {
+		return s.doMode(ctx, product)
+	}
 	var body io.Reader
-	body, err := meplatoapi.ReadJSON(s.product)
+	body, err := meplatoapi.ReadJSON(product)
 	if err != nil {
 		return nil, err
 	}
 	params := make(map[string]interface{})
 	params["area"] = s.area
 	params["pin"] = s.pin
+	if err := meplatoapi.RequireParams(params, "pin", "area"); err != nil {
+		return nil, err
+	}
 	path, err := meplatoapi.Expand("/catalogs/{pin}/{area}/products/upsert", params)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("POST", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -2877,7 +3372,10 @@ func (s *UpsertService) Do(ctx context.Context) (*UpsertProductResponse, error)
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2886,8 +3384,12 @@ func (s *UpsertService) Do(ctx context.Context) (*UpsertProductResponse, error)
 		return nil, err
 	}
 	ret := new(UpsertProductResponse)
-	if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
-		return nil, err
+	if !s.minimal {
+		if err := json.NewDecoder(res.Body).Decode(ret); err != nil {
+			return nil, err
+		}
 	}
+	ret.Location = res.Header.Get("Location")
+	ret.JobID = meplatoapi.JobID(ret.JobID, res)
 	return ret, nil
 }
@@ -1,49 +1,38 @@
 package products_test
 
 import (
-	"bufio"
 	"context"
-	"fmt"
+	"encoding/base64"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"path"
 	"strings"
 	"testing"
 
 	"github.com/meplato/store2-go-client/v2/products"
+	"github.com/meplato/store2-go-client/v2/storetest"
 )
 
 func getService(responseFile string) (*products.Service, *httptest.Server, error) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slurp, err := ioutil.ReadFile(path.Join("testdata", responseFile))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(string(slurp))), r)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer res.Body.Close()
-		bs, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(res.StatusCode)
-		fmt.Fprint(w, string(bs))
-	}))
+	return getServiceWithRoutes(func(r *http.Request) string {
+		return responseFile
+	})
+}
+
+// getServiceWithRoutes returns a service whose test server replies with
+// the response file returned by route for each incoming request.
+func getServiceWithRoutes(route func(r *http.Request) string) (*products.Service, *httptest.Server, error) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		return path.Join("testdata", route(r))
+	})
 
-	service, err := products.New(http.DefaultClient)
+	service, err := products.NewFromEnv()
 	if err != nil {
 		return service, nil, err
 	}
 	service.BaseURL = ts.URL
-	service.User = os.Getenv("STORE2_USER")
-	service.Password = os.Getenv("STORE2_PASSWORD")
 	return service, ts, nil
 }
 
@@ -66,6 +55,34 @@ func TestProductSearch(t *testing.T) {
 	}
 }
 
+func TestProductSearchSort(t *testing.T) {
+	service, ts, err := getService("products.search.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	sort := products.SortByNameAsc + "," + products.SortByCreatedDesc
+	res, err := service.Search().PIN("PIN").Area("work").Sort(sort).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil {
+		t.Fatal("expected response; got: nil")
+	}
+
+	res, err = service.Search().PIN("PIN").Area("work").Sort("nam").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error for invalid sort key; got: nil")
+	}
+	if res != nil {
+		t.Fatalf("expected no response; got: %v", res)
+	}
+}
+
 func TestProductGet(t *testing.T) {
 	service, ts, err := getService("products.get.success")
 	if err != nil {
@@ -85,6 +102,48 @@ func TestProductGet(t *testing.T) {
 	}
 }
 
+func TestProductGetWithAuth(t *testing.T) {
+	var auth []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		auth = append(auth, r.Header.Get("Authorization"))
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	service.User = "service-user"
+	service.Password = ""
+
+	_, err = service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").WithAuth("other-user", "secret").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("other-user:secret")),
+		"Basic " + base64.StdEncoding.EncodeToString([]byte("service-user:")),
+	}
+	if len(auth) != len(want) {
+		t.Fatalf("expected %d requests; got: %d", len(want), len(auth))
+	}
+	for i := range want {
+		if auth[i] != want[i] {
+			t.Errorf("request #%d: expected Authorization %q; got: %q", i, want[i], auth[i])
+		}
+	}
+	if service.User != "service-user" {
+		t.Errorf("expected service user to be unchanged; got: %q", service.User)
+	}
+}
+
 func TestProductCreate(t *testing.T) {
 	service, ts, err := getService("products.create.success")
 	if err != nil {
@@ -338,3 +397,174 @@ func TestProductUpsert(t *testing.T) {
 		t.Fatalf("expected link to product; got: %v", res.Link)
 	}
 }
+
+func TestProductCreateAndUpsertLocation(t *testing.T) {
+	const want = "https://store2.meplato.com/api/v2/catalogs/AD8CCDD5F9/work/products/MBA11"
+
+	service, ts, err := getService("products.create.location")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	cres, err := service.Create().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleCreateProduct()).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cres.Location != want {
+		t.Errorf("expected Location %q; got: %q", want, cres.Location)
+	}
+
+	service, ts, err = getService("products.upsert.location")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	ures, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleUpsertProduct()).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ures.Location != want {
+		t.Errorf("expected Location %q; got: %q", want, ures.Location)
+	}
+
+	service, ts, err = getService("products.upsert.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	ures, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleUpsertProduct()).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ures.Location != "" {
+		t.Errorf("expected no Location; got: %q", ures.Location)
+	}
+}
+
+func TestProductReplaceIncludeEmpty(t *testing.T) {
+	var sent map[string]json.RawMessage
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(data, &sent)
+		}
+		if err != nil {
+			t.Errorf("cannot read request body: %v", err)
+		}
+		return "products.replace.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	replace := &products.ReplaceProduct{
+		Name:       "Produkt 1000 (NEU!)",
+		Price:      2.50,
+		OrderUnit:  "PK",
+		Categories: []string{},
+		Keywords:   []string{"new"},
+	}
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(replace).IncludeEmpty("categories", "scalePrices", "keywords").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for field, want := range map[string]string{"categories": "[]", "scalePrices": "[]", "keywords": `["new"]`} {
+		if got := string(sent[field]); got != want {
+			t.Errorf("expected %s to be %s; got: %q", field, want, got)
+		}
+	}
+	if _, found := sent["eclasses"]; found {
+		t.Errorf("expected eclasses to be omitted; got: %s", sent["eclasses"])
+	}
+
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(replace).IncludeEmpty("name").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error for a field that is not a collection; got: nil")
+	}
+	_, err = service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(replace).IncludeEmpty("unknown").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error for an unknown field; got: nil")
+	}
+}
+
+func TestProductRequiresPINAndArea(t *testing.T) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx := context.Background()
+	if _, err := service.Get().PIN("AD8CCDD5F9").Spn("MBA11").Do(ctx); err == nil || !strings.Contains(err.Error(), `"area"`) {
+		t.Errorf("expected error about missing area; got: %v", err)
+	}
+	if _, err := service.Search().Area("work").Do(ctx); err == nil || !strings.Contains(err.Error(), `"pin"`) {
+		t.Errorf("expected error about missing pin; got: %v", err)
+	}
+	if err := service.Delete().PIN("AD8CCDD5F9").Area("work").Do(ctx); err == nil || !strings.Contains(err.Error(), `"spn"`) {
+		t.Errorf("expected error about missing spn; got: %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no requests to be sent; got: %d", requests)
+	}
+}
+
+func TestProductWriteWarnings(t *testing.T) {
+	service, ts, err := getService("products.write.warnings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	ctx := context.Background()
+	var warnings [][]string
+	cres, err := service.Create().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleCreateProduct()).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings = append(warnings, cres.Warnings)
+	name := "Changed"
+	ures, err := service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(&products.UpdateProduct{Name: &name}).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings = append(warnings, ures.Warnings)
+	rres, err := service.Replace().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(&products.ReplaceProduct{Name: name, Price: 1, OrderUnit: "PCE"}).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings = append(warnings, rres.Warnings)
+	ures2, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(products.ExampleUpsertProduct()).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings = append(warnings, ures2.Warnings)
+
+	for i, w := range warnings {
+		if len(w) != 2 {
+			t.Fatalf("#%d: expected %d warnings; got: %v", i, 2, w)
+		}
+		if !strings.Contains(w[0], "deprecated") {
+			t.Errorf("#%d: expected deprecation warning; got: %q", i, w[0])
+		}
+	}
+
+	// Responses without warnings have none
+	service, ts2, err := getService("products.update.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts2.Close()
+	ures, err = service.Update().PIN("AD8CCDD5F9").Area("work").Spn("MBA11").Product(&products.UpdateProduct{Name: &name}).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ures.Warnings) != 0 {
+		t.Errorf("expected no warnings; got: %v", ures.Warnings)
+	}
+}
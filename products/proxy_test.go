@@ -0,0 +1,41 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestProductWithProxy(t *testing.T) {
+	var host string
+	proxy := storetest.RouteServer(func(r *http.Request) string {
+		host = r.Host
+		return path.Join("testdata", "products.get.success")
+	})
+	defer proxy.Close()
+
+	service, err := products.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = "http://store.invalid/api/v2"
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.WithProxy(proxyURL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("1000").Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if host != "store.invalid" {
+		t.Fatalf("expected request for %q to be sent through the proxy; got: %q", "store.invalid", host)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"errors"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+func (s *Service) Ranking() *RankingService {
+	return NewRankingService(s)
+}
+
+// RankingService sets the ranking of a product in search results, i.e.
+// its boost factor, without touching any other field. It sends an update
+// with the boost factor only, which is less risky than a general Update
+// for adjustments made by merchandisers.
+type RankingService struct {
+	s           *Service
+	hdr_        map[string]interface{}
+	pin         string
+	area        string
+	spn         string
+	boostFactor *float64
+	minimal     bool
+}
+
+// NewRankingService creates a new instance of RankingService.
+func NewRankingService(s *Service) *RankingService {
+	rs := &RankingService{s: s, hdr_: make(map[string]interface{})}
+	return rs
+}
+
+// Area of the catalog, e.g. work or live.
+func (s *RankingService) Area(area string) *RankingService {
+	s.area = area
+	return s
+}
+
+// PIN of the catalog.
+func (s *RankingService) PIN(pin string) *RankingService {
+	s.pin = pin
+	return s
+}
+
+// SPN is the supplier part number of the product.
+func (s *RankingService) Spn(spn string) *RankingService {
+	s.spn = spn
+	return s
+}
+
+// BoostFactor is a positive or negative boost for the product. Use 0 to
+// reset it. Please consult your Store Manager before changing it.
+func (s *RankingService) BoostFactor(boostFactor float64) *RankingService {
+	s.boostFactor = &boostFactor
+	return s
+}
+
+// ReturnMinimal asks the server to respond without a body, see
+// UpdateService.ReturnMinimal.
+func (s *RankingService) ReturnMinimal() *RankingService {
+	s.minimal = true
+	return s
+}
+
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *RankingService) WithAuth(user, password string) *RankingService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
+// Do executes the operation. It returns an error without issuing a
+// request if BoostFactor has not been set.
+func (s *RankingService) Do(ctx context.Context) (*UpdateProductResponse, error) {
+	if s.boostFactor == nil {
+		return nil, errors.New("products: boost factor not set")
+	}
+	update := s.s.Update().PIN(s.pin).Area(s.area).Spn(s.spn).Product(&UpdateProduct{BoostFactor: s.boostFactor})
+	for k, v := range s.hdr_ {
+		update.hdr_[k] = v
+	}
+	if s.minimal {
+		update = update.ReturnMinimal()
+	}
+	return update.Do(ctx)
+}
@@ -0,0 +1,54 @@
+package products_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestProductRanking(t *testing.T) {
+	var bodies []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+strings.TrimSpace(string(data)))
+		return "products.update.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	for _, boost := range []float64{2.5, 0} {
+		res, err := service.Ranking().PIN("AD8CCDD5F9").Area("work").Spn("1000").BoostFactor(boost).Do(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res == nil {
+			t.Fatal("expected response; got: nil")
+		}
+	}
+	want := []string{
+		`POST /catalogs/AD8CCDD5F9/work/products/1000 {"boostFactor":2.5}`,
+		`POST /catalogs/AD8CCDD5F9/work/products/1000 {"boostFactor":0}`,
+	}
+	if len(bodies) != len(want) {
+		t.Fatalf("expected %d requests; got: %d", len(want), len(bodies))
+	}
+	for i := range want {
+		if bodies[i] != want[i] {
+			t.Errorf("#%d: expected %s; got: %s", i, want[i], bodies[i])
+		}
+	}
+
+	if _, err := service.Ranking().PIN("AD8CCDD5F9").Area("work").Spn("1000").Do(context.Background()); err == nil {
+		t.Fatal("expected error without boost factor; got: nil")
+	}
+	if len(bodies) != len(want) {
+		t.Fatalf("expected no request without boost factor; got: %d requests", len(bodies))
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// GetRaw executes the operation and returns the product as raw JSON, as
+// sent by the server. Unlike Do, it keeps fields the client does not
+// model yet, e.g. after the API has been extended. Decode it into your
+// own type or a map to access them, or into Product to get the known
+// fields as well.
+func (s *GetService) GetRaw(ctx context.Context) (json.RawMessage, error) {
+	res, err := s.DoResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer meplatoapi.CloseBody(res)
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, errors.New("products: response is not valid JSON")
+	}
+	return json.RawMessage(data), nil
+}
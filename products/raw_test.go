@@ -0,0 +1,57 @@
+package products_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductGetRaw(t *testing.T) {
+	service, ts, err := getService("products.get.extended")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	raw, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").GetRaw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fields the client does not model yet are retained
+	var extended struct {
+		Sustainability struct {
+			CO2Footprint float64 `json:"co2Footprint"`
+		} `json:"sustainability"`
+	}
+	if err := json.Unmarshal(raw, &extended); err != nil {
+		t.Fatal(err)
+	}
+	if want := 1.25; extended.Sustainability.CO2Footprint != want {
+		t.Errorf("expected CO2 footprint %v; got: %v", want, extended.Sustainability.CO2Footprint)
+	}
+
+	// Known fields decode as usual
+	var p products.Product
+	if err := json.Unmarshal(raw, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Spn != "50763599" {
+		t.Errorf("expected SPN %q; got: %q", "50763599", p.Spn)
+	}
+}
+
+func TestProductGetRawNotFound(t *testing.T) {
+	service, ts, err := getService("products.get.not_found")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	raw, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("unknown").GetRaw(context.Background())
+	if err == nil {
+		t.Fatalf("expected error; got: %s", raw)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+)
+
+// ProductContext is a product along with the catalog, project, and
+// merchant it belongs to.
+type ProductContext struct {
+	// Product is the product that has been resolved.
+	Product *Product
+	// Catalog is the catalog of the product.
+	Catalog *catalogs.Catalog
+	// CatalogName is the name of the catalog.
+	CatalogName string
+	// ProjectName is the name of the project of the catalog.
+	ProjectName string
+	// MerchantName is the name of the merchant of the catalog.
+	MerchantName string
+}
+
+// ResolveContext fetches the catalog of p with service and returns p
+// along with the names of its catalog, project, and merchant.
+//
+// The PIN of the catalog is taken from the SelfLink of p. If p has no
+// SelfLink, ResolveContext searches the catalogs for the one with the
+// CatalogID of p, which may take several requests.
+func ResolveContext(ctx context.Context, service *catalogs.Service, p *Product) (*ProductContext, error) {
+	if p == nil {
+		return nil, errors.New("products: product is nil")
+	}
+	var c *catalogs.Catalog
+	if pin := pinFromLink(p.SelfLink); pin != "" {
+		var err error
+		c, err = service.Get().PIN(pin).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if p.CatalogID != 0 && c.ID != p.CatalogID {
+			return nil, fmt.Errorf("products: catalog %s has ID %d, expected %d", pin, c.ID, p.CatalogID)
+		}
+	} else {
+		if p.CatalogID == 0 {
+			return nil, errors.New("products: product has neither a self link nor a catalog ID")
+		}
+		var err error
+		c, err = findCatalogByID(ctx, service, p.CatalogID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := &ProductContext{
+		Product:      p,
+		Catalog:      c,
+		CatalogName:  c.Name,
+		ProjectName:  c.ProjectName,
+		MerchantName: c.MerchantName,
+	}
+	if ret.ProjectName == "" && c.Project != nil {
+		ret.ProjectName = c.Project.Name
+	}
+	return ret, nil
+}
+
+// pinFromLink returns the PIN of the catalog in link, a URL like
+// https://store.meplato.com/api/v2/catalogs/{pin}/{area}/products/{spn}.
+// It returns an empty string if link has no PIN.
+func pinFromLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(u.Path, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "catalogs" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// findCatalogByID pages through the catalogs and returns the one with
+// the given ID.
+func findCatalogByID(ctx context.Context, service *catalogs.Service, id int64) (*catalogs.Catalog, error) {
+	it := service.Search().Take(100).Iterator()
+	for {
+		items, ok, err := it.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("products: catalog with ID %d not found", id)
+		}
+		for _, c := range items {
+			if c.ID == id {
+				return c, nil
+			}
+		}
+	}
+}
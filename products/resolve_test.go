@@ -0,0 +1,72 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/catalogs"
+	"github.com/meplato/store2-go-client/v2/products"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestResolveContext(t *testing.T) {
+	var paths []string
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		paths = append(paths, r.URL.Path)
+		if strings.HasPrefix(r.URL.Path, "/catalogs/") {
+			return path.Join("..", "catalogs", "testdata", "catalogs.get.success")
+		}
+		return path.Join("..", "catalogs", "testdata", "catalogs.search.success")
+	})
+	defer ts.Close()
+	service, err := catalogs.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	// Resolve via the PIN in the self link
+	p := &products.Product{
+		Spn:       "50763599",
+		CatalogID: 14,
+		SelfLink:  "https://store2.meplato.com/api/v2/catalogs/5094310527/work/products/50763599",
+	}
+	pc, err := products.ResolveContext(context.Background(), service, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pc.Product != p || pc.Catalog == nil {
+		t.Fatalf("expected product and catalog; got: %+v", pc)
+	}
+	if pc.CatalogName != "Ersatzteile" || pc.ProjectName != "BigBuy" || pc.MerchantName != "ABC Elektronik" {
+		t.Fatalf("expected names of catalog, project, and merchant; got: %+v", pc)
+	}
+	if len(paths) != 1 || paths[0] != "/catalogs/5094310527" {
+		t.Fatalf("expected a single get of the catalog; got: %v", paths)
+	}
+
+	// Resolve via the catalog ID
+	paths = nil
+	pc, err = products.ResolveContext(context.Background(), service, &products.Product{Spn: "50763599", CatalogID: 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pc.Catalog.PIN != "5094310527" || pc.ProjectName != "BigBuy" {
+		t.Fatalf("expected catalog 5094310527 of project BigBuy; got: %+v", pc)
+	}
+	if len(paths) != 1 || paths[0] != "/catalogs" {
+		t.Fatalf("expected a search for catalogs; got: %v", paths)
+	}
+
+	// Mismatching catalog ID
+	p.CatalogID = 12
+	if _, err := products.ResolveContext(context.Background(), service, p); err == nil {
+		t.Fatal("expected error for mismatching catalog ID; got: nil")
+	}
+	if _, err := products.ResolveContext(context.Background(), service, &products.Product{CatalogID: 99}); err == nil {
+		t.Fatal("expected error for unknown catalog; got: nil")
+	}
+}
@@ -0,0 +1,183 @@
+package products_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductCreateRetry(t *testing.T) {
+	defer func(d time.Duration) { meplatoapi.RetryDelay = d }(meplatoapi.RetryDelay)
+	meplatoapi.RetryDelay = time.Millisecond
+
+	var mu sync.Mutex
+	var bodies []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("cannot read request body: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(data))
+		if len(bodies) < 3 {
+			return "products.create.unavailable"
+		}
+		return "products.create.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.MaxRetries = 2
+
+	create := &products.CreateProduct{
+		Spn:       "1000",
+		Name:      "Produkt 1000",
+		Price:     4.99,
+		OrderUnit: "PCE",
+	}
+	cres, err := service.Create().PIN("AD8CCDD5F9").Area("work").Product(create).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cres == nil {
+		t.Fatal("expected response; got: nil")
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("expected %d requests; got: %d", 3, len(bodies))
+	}
+	for i, body := range bodies {
+		if body == "" || body != bodies[0] {
+			t.Errorf("expected request %d to send body %q; got: %q", i, bodies[0], body)
+		}
+	}
+}
+
+func TestProductCreateRetryExhausted(t *testing.T) {
+	defer func(d time.Duration) { meplatoapi.RetryDelay = d }(meplatoapi.RetryDelay)
+	meplatoapi.RetryDelay = time.Millisecond
+
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		return "products.create.unavailable"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.MaxRetries = 1
+
+	create := &products.CreateProduct{Spn: "1000", Name: "Produkt 1000", Price: 4.99, OrderUnit: "PCE"}
+	_, err = service.Create().PIN("AD8CCDD5F9").Area("work").Product(create).Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+	apiErr, ok := err.(*meplatoapi.Error)
+	if !ok {
+		t.Fatalf("expected *meplatoapi.Error; got: %T", err)
+	}
+	if apiErr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected code %d; got: %d", http.StatusServiceUnavailable, apiErr.Code)
+	}
+	if requests != 2 {
+		t.Errorf("expected %d requests; got: %d", 2, requests)
+	}
+}
+
+func TestProductDeleteRetryNotFound(t *testing.T) {
+	defer func(d time.Duration) { meplatoapi.RetryDelay = d }(meplatoapi.RetryDelay)
+	meplatoapi.RetryDelay = time.Millisecond
+
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		if requests == 1 {
+			return "products.create.unavailable"
+		}
+		return "products.delete.not_found"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.MaxRetries = 2
+
+	err = service.Delete().PIN("AD8CCDD5F9").Area("work").Spn("1000").Do(context.Background())
+	if err != nil {
+		t.Fatalf("expected retried delete with 404 to succeed; got: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected %d requests; got: %d", 2, requests)
+	}
+}
+
+func TestProductDeleteNotFoundWithRetries(t *testing.T) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		return "products.delete.not_found"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.MaxRetries = 2
+
+	err = service.Delete().PIN("AD8CCDD5F9").Area("work").Spn("1000").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+	apiErr, ok := err.(*meplatoapi.Error)
+	if !ok {
+		t.Fatalf("expected *meplatoapi.Error; got: %T", err)
+	}
+	if apiErr.Code != http.StatusNotFound {
+		t.Errorf("expected code %d; got: %d", http.StatusNotFound, apiErr.Code)
+	}
+	if requests != 1 {
+		t.Errorf("expected %d requests; got: %d", 1, requests)
+	}
+}
+
+func TestProductObserveAttempts(t *testing.T) {
+	defer func(d time.Duration) { meplatoapi.RetryDelay = d }(meplatoapi.RetryDelay)
+	meplatoapi.RetryDelay = time.Millisecond
+
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		if requests <= 2 {
+			return "products.create.unavailable"
+		}
+		return "products.get.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.MaxRetries = 3
+
+	var observed []int
+	service.ObserveAttempts = func(req *http.Request, attempts int) {
+		if req.Method != "GET" {
+			t.Errorf("expected GET request; got: %s", req.Method)
+		}
+		observed = append(observed, attempts)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := service.Get().PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(observed) != 2 || observed[0] != 3 || observed[1] != 1 {
+		t.Errorf("expected attempts [3 1]; got: %v", observed)
+	}
+}
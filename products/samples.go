@@ -0,0 +1,103 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+// ExampleUpsertProduct returns a sample product with all commonly used
+// fields populated. The required fields are Spn, Name, Price, and
+// OrderUnit; everything else is optional. Use it as a template for your
+// own payloads, for documentation, or as a test fixture. Every call
+// returns a new instance that can be modified freely.
+func ExampleUpsertProduct() *UpsertProduct {
+	return &UpsertProduct{
+		// Required fields
+		Spn:       "MBA11",
+		Name:      "Apple MacBook Air 11\"",
+		Price:     1199.00,
+		OrderUnit: "PCE",
+
+		// Pricing
+		Currency:    "EUR",
+		PriceQty:    float64Ptr(1),
+		ListPrice:   float64Ptr(1299.00),
+		TaxCode:     "MWST19",
+		TaxRate:     0.19,
+		ContentUnit: "PCE",
+		CuPerOu:     float64Ptr(1),
+		QuantityMin: float64Ptr(1),
+		QuantityMax: float64Ptr(10),
+		Leadtime:    float64Ptr(3),
+		KeepPrice:   boolPtr(false),
+		ScalePrices: []*ScalePrice{
+			{Lbound: 1, Price: 1199.00},
+			{Lbound: 5, Price: 1149.00},
+		},
+
+		// Description and identification
+		Description:  "The thinnest and lightest MacBook Air ever.",
+		Manufacturer: "Apple",
+		Mpn:          "MD711D/A",
+		Gtin:         "0885909712370",
+		Brand:        "Apple",
+		Country:      "DE",
+		Categories:   []string{"Notebooks"},
+		Keywords:     []string{"notebook", "laptop", "macbook"},
+		Eclasses: []*Eclass{
+			{Version: "5.1", Code: "24010202"},
+		},
+		Unspscs: []*Unspsc{
+			{Version: "16.0901", Code: "43211503"},
+		},
+		Features: []*Feature{
+			{Kind: "free", Name: "Weight", Unit: "KGM", Values: []string{"1.08"}},
+		},
+		Conditions: []*Condition{
+			{Kind: "new", Text: "Brand new"},
+		},
+		References: []*Reference{
+			{Kind: "accessories", Spn: "MBA11-CHARGER"},
+		},
+
+		// Media
+		Image:     "https://www.example.com/images/mba11.jpg",
+		Thumbnail: "https://www.example.com/images/mba11-thumb.jpg",
+		Datasheet: "https://www.example.com/datasheets/mba11.pdf",
+		Blobs: []*Blob{
+			{Kind: "image", Source: "https://www.example.com/images/mba11-side.jpg", Text: "Side view"},
+		},
+
+		// Availability and visibility
+		Availability: &Availability{
+			Message: "in stock",
+			Qty:     float64Ptr(42),
+		},
+		Visible:   boolPtr(true),
+		Orderable: boolPtr(true),
+	}
+}
+
+// ExampleCreateProduct returns a sample product to be used with
+// CreateService. It has the same contents as ExampleUpsertProduct.
+func ExampleCreateProduct() *CreateProduct {
+	// CreateProduct and UpsertProduct have identical fields, so the
+	// compiler ensures this conversion stays valid.
+	p := CreateProduct(*ExampleUpsertProduct())
+	return &p
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,36 @@
+package products_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestExampleProducts(t *testing.T) {
+	for _, p := range []interface{}{products.ExampleUpsertProduct(), products.ExampleCreateProduct()} {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range []string{"spn", "name", "price", "ou", "currency", "eclasses", "scalePrices"} {
+			if _, found := m[field]; !found {
+				t.Errorf("%T: expected field %q in %s", p, field, data)
+			}
+		}
+	}
+}
+
+func TestExampleUpsertProductIsIndependent(t *testing.T) {
+	a := products.ExampleUpsertProduct()
+	a.Name = "Changed"
+	a.Eclasses[0].Code = "0"
+	b := products.ExampleUpsertProduct()
+	if b.Name == a.Name || b.Eclasses[0].Code == a.Eclasses[0].Code {
+		t.Fatal("expected each call to return a new instance")
+	}
+}
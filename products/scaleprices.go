@@ -0,0 +1,71 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortAndValidateScalePrices sorts prices by their lower bound in place
+// and checks them for problems the server would reject: empty entries,
+// negative lower bounds or prices, and duplicate lower bounds, i.e. two
+// prices for the same quantity. It returns nil if prices are valid and
+// ValidationErrors otherwise. The field of each error refers to the
+// position after sorting.
+func SortAndValidateScalePrices(prices []*ScalePrice) error {
+	var errs ValidationErrors
+	sort.SliceStable(prices, func(i, j int) bool {
+		if prices[i] == nil || prices[j] == nil {
+			return prices[j] != nil
+		}
+		return prices[i].Lbound < prices[j].Lbound
+	})
+	for i, p := range prices {
+		field := fmt.Sprintf("scalePrices[%d]", i)
+		switch {
+		case p == nil:
+			errs = append(errs, &ValidationError{Field: field, Message: "is empty"})
+			continue
+		case p.Lbound < 0:
+			errs = append(errs, &ValidationError{Field: field, Message: "lower bound must not be negative"})
+		case i > 0 && prices[i-1] != nil && prices[i-1].Lbound == p.Lbound:
+			errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf("lower bound %v overlaps with scalePrices[%d]", p.Lbound, i-1)})
+		}
+		if p.Price < 0 {
+			errs = append(errs, &ValidationError{Field: field, Message: "price must not be negative"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateScalePrices sorts the scale prices of the product and validates
+// them with SortAndValidateScalePrices before sending it to the server.
+// If they are invalid, Do returns the ValidationErrors without issuing a
+// request.
+func (s *CreateService) ValidateScalePrices() *CreateService {
+	s.validateScalePrices = true
+	return s
+}
+
+// ValidateScalePrices sorts the scale prices of the product and validates
+// them with SortAndValidateScalePrices before sending it to the server.
+// If they are invalid, Do returns the ValidationErrors without issuing a
+// request.
+func (s *UpsertService) ValidateScalePrices() *UpsertService {
+	s.validateScalePrices = true
+	return s
+}
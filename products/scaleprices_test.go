@@ -0,0 +1,86 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestSortAndValidateScalePrices(t *testing.T) {
+	prices := []*products.ScalePrice{
+		{Lbound: 10, Price: 8.50},
+		{Lbound: 1, Price: 10.00},
+		{Lbound: 100, Price: 7.25},
+	}
+	if err := products.SortAndValidateScalePrices(prices); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{1, 10, 100} {
+		if prices[i].Lbound != want {
+			t.Errorf("%d. expected lower bound %v; got: %v", i, want, prices[i].Lbound)
+		}
+	}
+
+	prices = []*products.ScalePrice{
+		{Lbound: 10, Price: 8.50},
+		{Lbound: 1, Price: 10.00},
+		{Lbound: 10, Price: 8.00},
+		{Lbound: -1, Price: 12.00},
+	}
+	err := products.SortAndValidateScalePrices(prices)
+	errs, ok := err.(products.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected %d errors; got: %v", 2, errs)
+	}
+	if errs[0].Field != "scalePrices[0]" || errs[1].Field != "scalePrices[3]" {
+		t.Fatalf("expected errors for scalePrices[0] and scalePrices[3]; got: %v", errs)
+	}
+
+	if err := products.SortAndValidateScalePrices([]*products.ScalePrice{nil}); err == nil {
+		t.Fatal("expected error for empty scale price; got: nil")
+	}
+	if err := products.SortAndValidateScalePrices(nil); err != nil {
+		t.Fatalf("expected no error without scale prices; got: %v", err)
+	}
+}
+
+func TestProductUpsertValidateScalePrices(t *testing.T) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		return "products.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := products.ExampleUpsertProduct()
+	p.ScalePrices = []*products.ScalePrice{
+		{Lbound: 10, Price: 8.50},
+		{Lbound: 10, Price: 8.00},
+	}
+	_, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).ValidateScalePrices().Do(context.Background())
+	if _, ok := err.(products.ValidationErrors); !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no request to be sent; got: %d", requests)
+	}
+
+	p.ScalePrices[1].Lbound = 1
+	if _, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).ValidateScalePrices().Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected %d request; got: %d", 1, requests)
+	}
+	if p.ScalePrices[0].Lbound != 1 {
+		t.Fatalf("expected scale prices to be sorted; got lower bound %v first", p.ScalePrices[0].Lbound)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// ErrScrollExpired is returned by ScrollService.Do when the page token is
+// no longer known to the server, e.g. because the next page was not
+// requested within the keep-alive period of 2 minutes. Use errors.Is to
+// test for it; the underlying *meplatoapi.Error remains available via
+// errors.As.
+var ErrScrollExpired = errors.New("products: scroll expired")
+
+// scrollExpiredError wraps the server error of an expired scroll.
+type scrollExpiredError struct {
+	err error
+}
+
+func (e *scrollExpiredError) Error() string {
+	return ErrScrollExpired.Error() + ": " + e.err.Error()
+}
+
+func (e *scrollExpiredError) Is(target error) bool {
+	return target == ErrScrollExpired
+}
+
+func (e *scrollExpiredError) Unwrap() error {
+	return e.err
+}
+
+// isScrollExpired reports whether err indicates that the server no longer
+// knows the page token of a scroll request.
+func isScrollExpired(err error) bool {
+	var e *meplatoapi.Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	switch e.Code {
+	case http.StatusGone:
+		return true
+	case http.StatusNotFound, http.StatusBadRequest:
+		msg := strings.ToLower(e.Message + " " + strings.Join(e.Details, " "))
+		return strings.Contains(msg, "scroll") || strings.Contains(msg, "page token") || strings.Contains(msg, "pagetoken")
+	}
+	return false
+}
+
+// MaxPages limits Pages and Collect to the first k pages that contain
+// products. If k is less than or equal to zero, all pages are fetched
+// (default).
+func (s *ScrollService) MaxPages(k int) *ScrollService {
+	s.maxPages = k
+	return s
+}
+
+// Limit limits Pages and Collect to the first n products. If n is less
+// than or equal to zero, all products are fetched (default).
+func (s *ScrollService) Limit(n int) *ScrollService {
+	s.limit = n
+	return s
+}
+
+// Pages scrolls through the products, starting at the page token (if any),
+// and calls fn for every page until there are no more pages or the limits
+// set by MaxPages and Limit are reached. If fn returns an error, Pages
+// stops and returns that error. Use Processed and TotalItems of each page
+// to report progress, e.g. "processed 2000 of 5000".
+func (s *ScrollService) Pages(ctx context.Context, fn func(*ScrollResponse) error) error {
+	var c scrollCounter
+	for {
+		res, err := s.Do(ctx)
+		if err != nil {
+			return err
+		}
+		more := s.advance(res, &c)
+		if err := fn(res); err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// scrollCounter counts the pages and products seen while scrolling, and
+// remembers the total number of products.
+type scrollCounter struct {
+	pages int
+	items int
+	total int64
+}
+
+// advance applies the limits of MaxPages and Limit to res, counts it with
+// c, and prepares s to fetch the next page. It reports whether there are
+// more pages to fetch. As the server may return TotalItems on the first
+// page only, advance copies it to subsequent pages, and it sets Processed.
+func (s *ScrollService) advance(res *ScrollResponse, c *scrollCounter) bool {
+	if len(res.Items) > 0 {
+		c.pages++
+	}
+	if s.limit > 0 && c.items+len(res.Items) > s.limit {
+		res.Items = res.Items[:s.limit-c.items]
+	}
+	c.items += len(res.Items)
+	if res.TotalItems > 0 {
+		c.total = res.TotalItems
+	} else {
+		res.TotalItems = c.total
+	}
+	res.Processed = int64(c.items)
+	if res.PageToken == "" {
+		return false
+	}
+	if s.maxPages > 0 && c.pages >= s.maxPages {
+		return false
+	}
+	if s.limit > 0 && c.items >= s.limit {
+		return false
+	}
+	s.PageToken(res.PageToken)
+	return true
+}
+
+// Collect scrolls through the products like Pages and returns all
+// products it has found.
+func (s *ScrollService) Collect(ctx context.Context) ([]*Product, error) {
+	var items []*Product
+	err := s.Pages(ctx, func(res *ScrollResponse) error {
+		items = append(items, res.Items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ErrNotJSON is returned by ScrollService.Do if a content type other than
+// JSON has been requested with Accept. Use DoResponse to read the
+// response in that case.
+var ErrNotJSON = errors.New("products: Do only decodes JSON; use DoResponse for other content types")
+
+// Accept requests the products in the given content type, e.g. text/csv
+// or application/xml, if the server supports it. The default is JSON.
+// For content types other than JSON, use DoResponse and read the body
+// directly; Do returns ErrNotJSON.
+func (s *ScrollService) Accept(contentType string) *ScrollService {
+	s.hdr_["Accept"] = contentType
+	return s
+}
+
+// accept returns the media type requested with Accept, or an empty string
+// if JSON is requested.
+func (s *ScrollService) accept() string {
+	v, ok := s.hdr_["Accept"]
+	if !ok {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(fmt.Sprint(v))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if mediaType == "application/json" {
+		return ""
+	}
+	return mediaType
+}
+
+// checkContentType returns an error if the server responded with a
+// content type other than the one requested with Accept.
+func (s *ScrollService) checkContentType(res *http.Response) error {
+	want := s.accept()
+	if want == "" {
+		return nil
+	}
+	got, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || got != want {
+		return fmt.Errorf("products: requested %s, but the server responded with %q", want, res.Header.Get("Content-Type"))
+	}
+	return nil
+}
+
+// pageTokenFromLink returns the pageToken parameter of link, e.g. the URL
+// of the next page from a Link header. It returns an empty string if link
+// is invalid or has no page token.
+func pageTokenFromLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("pageToken")
+}
@@ -0,0 +1,382 @@
+package products_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductScrollExpired(t *testing.T) {
+	service, ts, err := getService("products.scroll.expired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").PageToken("expired").Do(context.Background())
+	if !errors.Is(err, products.ErrScrollExpired) {
+		t.Fatalf("expected %v; got: %v", products.ErrScrollExpired, err)
+	}
+	if res != nil {
+		t.Fatalf("expected no response; got: %v", res)
+	}
+
+	// Without a page token, there is nothing that could have expired
+	_, err = service.Scroll().PIN("AD8CCDD5F9").Area("work").Do(context.Background())
+	if err == nil {
+		t.Fatal("expected error; got: nil")
+	}
+	if errors.Is(err, products.ErrScrollExpired) {
+		t.Fatalf("expected a plain server error; got: %v", err)
+	}
+}
+
+func TestProductScrollRestartOnExpiry(t *testing.T) {
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.URL.Query().Get("pageToken") != "" {
+			return "products.scroll.expired"
+		}
+		return "products.scroll.success.1"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service == nil {
+		t.Fatal("expected service; got: nil")
+	}
+	defer ts.Close()
+
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").PageToken("expired").RestartOnExpiry(true).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil {
+		t.Fatal("expected response; got: nil")
+	}
+	if !res.Restarted {
+		t.Fatal("expected scroll to be restarted")
+	}
+	if res.PageToken == "" {
+		t.Fatalf("expected page token of the first page; got: %q", res.PageToken)
+	}
+}
+
+func getScrollService(t *testing.T) (*products.Service, func() int, func()) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		if r.URL.Query().Get("pageToken") == "" {
+			return "products.scroll.success.1"
+		}
+		return "products.scroll.success.2"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, func() int { return requests }, ts.Close
+}
+
+func TestProductScrollMaxPages(t *testing.T) {
+	service, requests, closer := getScrollService(t)
+	defer closer()
+
+	var pages int
+	err := service.Scroll().PIN("AD8CCDD5F9").Area("work").MaxPages(2).Pages(context.Background(), func(res *products.ScrollResponse) error {
+		pages++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first page of a scroll has no products and does not count
+	if pages != 3 {
+		t.Fatalf("expected %d pages; got: %d", 3, pages)
+	}
+	if n := requests(); n != 3 {
+		t.Fatalf("expected %d requests; got: %d", 3, n)
+	}
+}
+
+func TestProductScrollLimit(t *testing.T) {
+	service, requests, closer := getScrollService(t)
+	defer closer()
+
+	items, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Limit(3).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected %d products; got: %d", 3, len(items))
+	}
+	if n := requests(); n != 2 {
+		t.Fatalf("expected %d requests; got: %d", 2, n)
+	}
+}
+
+func TestProductScrollPageSize(t *testing.T) {
+	var takes []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		takes = append(takes, r.URL.Query().Get("take"))
+		if r.URL.Query().Get("pageToken") == "" {
+			return "products.scroll.success.1"
+		}
+		return "products.scroll.success.2"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	_, err = service.Scroll().PIN("AD8CCDD5F9").Area("work").PageSize(500).MaxPages(1).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(takes) != 2 || takes[0] != "500" || takes[1] != "500" {
+		t.Fatalf("expected take=500 on every page; got: %q", takes)
+	}
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{5000, "5000"},
+		{0, ""},
+		{-1, ""},
+	}
+	for _, tt := range tests {
+		takes = nil
+		if _, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").PageSize(100).PageSize(tt.n).Do(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(takes) != 1 || takes[0] != tt.want {
+			t.Errorf("PageSize(%d): expected take=%q; got: %q", tt.n, tt.want, takes)
+		}
+	}
+}
+
+func TestProductScrollPagesError(t *testing.T) {
+	service, requests, closer := getScrollService(t)
+	defer closer()
+
+	stop := errors.New("stop")
+	err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Pages(context.Background(), func(res *products.ScrollResponse) error {
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("expected %v; got: %v", stop, err)
+	}
+	if n := requests(); n != 1 {
+		t.Fatalf("expected %d request; got: %d", 1, n)
+	}
+}
+
+func TestProductScrollIterator(t *testing.T) {
+	service, requests, closer := getScrollService(t)
+	defer closer()
+
+	it := service.Scroll().PIN("AD8CCDD5F9").Area("work").MaxPages(2).Iterator()
+	var pages int
+	for {
+		items, ok, err := it.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		if len(items) == 0 {
+			t.Fatal("expected iterator to skip empty pages")
+		}
+		pages++
+	}
+	if pages != 2 {
+		t.Fatalf("expected %d pages; got: %d", 2, pages)
+	}
+	if n := requests(); n != 3 {
+		t.Fatalf("expected %d requests; got: %d", 3, n)
+	}
+}
+
+func TestProductScrollDoResponse(t *testing.T) {
+	service, ts, err := getService("products.scroll.success.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.SetMaxConcurrency(1)
+
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").PageToken("token").DoResponse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := service.InFlight(); n != 1 {
+		t.Fatalf("expected the request to be in flight until the body is closed; got: %d", n)
+	}
+
+	// Decode the products one by one instead of the whole page at once
+	dec := json.NewDecoder(res.Body)
+	var n int
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok == "items" {
+			break
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	for dec.More() {
+		var p products.Product
+		if err := dec.Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		if p.Spn == "" {
+			t.Fatal("expected product with SPN")
+		}
+		n++
+	}
+	res.Body.Close()
+	if n != 200 {
+		t.Fatalf("expected %d products; got: %d", 200, n)
+	}
+	if n := service.InFlight(); n != 0 {
+		t.Fatalf("expected no requests in flight after closing the body; got: %d", n)
+	}
+}
+
+func TestProductScrollDoResponseExpired(t *testing.T) {
+	service, ts, err := getService("products.scroll.expired")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	service.SetMaxConcurrency(1)
+
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").PageToken("token").DoResponse(context.Background())
+	if !errors.Is(err, products.ErrScrollExpired) {
+		t.Fatalf("expected %v; got: %v", products.ErrScrollExpired, err)
+	}
+	if res != nil {
+		t.Fatalf("expected no response; got: %v", res)
+	}
+	if n := service.InFlight(); n != 0 {
+		t.Fatalf("expected the body of the error to be closed; got %d in flight", n)
+	}
+}
+
+func TestProductScrollAccept(t *testing.T) {
+	var accept string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		accept = r.Header.Get("Accept")
+		if accept == "text/csv" {
+			return "products.scroll.csv"
+		}
+		return "products.scroll.success.2"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Accept("text/csv").DoResponse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if accept != "text/csv" {
+		t.Fatalf("expected Accept %q; got: %q", "text/csv", accept)
+	}
+	csvr := csv.NewReader(res.Body)
+	csvr.Comma = ';'
+	records, err := csvr.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected %d records; got: %d", 3, len(records))
+	}
+
+	// Do only decodes JSON
+	if _, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Accept("text/csv").Do(context.Background()); err != products.ErrNotJSON {
+		t.Fatalf("expected %v; got: %v", products.ErrNotJSON, err)
+	}
+
+	// The server must respond with the requested content type
+	if _, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Accept("application/xml").DoResponse(context.Background()); err == nil {
+		t.Fatal("expected error for JSON response to XML request; got: nil")
+	}
+
+	// JSON is the default
+	if _, err := service.Scroll().PIN("AD8CCDD5F9").Area("work").Accept("application/json").Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProductScrollProgress(t *testing.T) {
+	// The server returns TotalItems on the first page only
+	var requests int
+	newService := func() (*products.Service, func()) {
+		requests = 0
+		service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+			requests++
+			if requests == 1 {
+				return "products.scroll.success.1"
+			}
+			return "products.scroll.no_total"
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service, ts.Close
+	}
+
+	service, closer := newService()
+	defer closer()
+	var processed []int64
+	err := service.Scroll().PIN("AD8CCDD5F9").Area("work").MaxPages(2).Pages(context.Background(), func(res *products.ScrollResponse) error {
+		if res.TotalItems != 98621 {
+			t.Errorf("expected TotalItems %d on every page; got: %d", 98621, res.TotalItems)
+		}
+		processed = append(processed, res.Processed)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{0, 3, 6}; len(processed) != len(want) || processed[0] != want[0] || processed[1] != want[1] || processed[2] != want[2] {
+		t.Fatalf("expected processed %v; got: %v", want, processed)
+	}
+
+	service, closer = newService()
+	defer closer()
+	it := service.Scroll().PIN("AD8CCDD5F9").Area("work").MaxPages(2).Iterator()
+	if total := it.Total(); total != 0 {
+		t.Fatalf("expected total to be unknown before the first page; got: %d", total)
+	}
+	for {
+		_, ok, err := it.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		if total := it.Total(); total != 98621 {
+			t.Errorf("expected total %d; got: %d", 98621, total)
+		}
+	}
+	if n := it.Processed(); n != 6 {
+		t.Fatalf("expected %d processed products; got: %d", 6, n)
+	}
+}
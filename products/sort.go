@@ -0,0 +1,40 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// Sort orders accepted by SearchService.Sort. Multiple orders can be
+// combined with a comma, e.g. SortByNameAsc + "," + SortByCreatedDesc.
+const (
+	SortByNameAsc     = "name"
+	SortByNameDesc    = "-name"
+	SortBySpnAsc      = "spn"
+	SortBySpnDesc     = "-spn"
+	SortByIDAsc       = "id"
+	SortByIDDesc      = "-id"
+	SortByCreatedAsc  = "created"
+	SortByCreatedDesc = "-created"
+)
+
+// sortKeys are the keys the server accepts for sorting products.
+var sortKeys = []string{"name", "spn", "id", "created"}
+
+// ValidateSort returns an error if sort contains a key that is not
+// supported for sorting products. An empty sort is valid and results in
+// the default order by score.
+func ValidateSort(sort string) error {
+	return meplatoapi.ValidateSort(sort, sortKeys...)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"html"
+	"strings"
+)
+
+// blockTags are the HTML elements that StripHTML replaces with a line
+// break.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "td": true, "th": true,
+	"tr": true, "ul": true,
+}
+
+// rawTextTags are the HTML elements whose content StripHTML removes
+// along with the tags.
+var rawTextTags = map[string]bool{
+	"script": true, "style": true,
+}
+
+// StripHTML converts HTML, e.g. in the description of a product, to plain
+// text. It removes all tags and comments, as well as the content of script
+// and style elements, and decodes entities like &amp; and &uuml;. Block
+// elements like p, div, li, and br are replaced by line breaks. Whitespace
+// is collapsed, and empty lines are removed. A "<" that does not start a
+// tag, e.g. in "a < b", is kept.
+func StripHTML(s string) string {
+	var text strings.Builder
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			text.WriteString(html.UnescapeString(s))
+			break
+		}
+		text.WriteString(html.UnescapeString(s[:i]))
+		s = s[i:]
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				break
+			}
+			s = s[4+end+3:]
+			continue
+		}
+		name, closing, n := parseTag(s)
+		if n == 0 {
+			// Not a tag
+			text.WriteByte('<')
+			s = s[1:]
+			continue
+		}
+		s = s[n:]
+		if blockTags[name] {
+			text.WriteByte('\n')
+		}
+		if rawTextTags[name] && !closing {
+			end := strings.Index(strings.ToLower(s), "</"+name)
+			if end < 0 {
+				break
+			}
+			s = s[end:]
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, strings.Join(fields, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseTag parses the tag at the start of s, e.g. <p class="x"> or </p>.
+// It returns the lower-case name of the tag, whether it is a closing tag,
+// and the length of the tag including the angle brackets. n is 0 if s
+// does not start with a tag. Declarations like <!DOCTYPE html> are
+// treated as tags without a name.
+func parseTag(s string) (name string, closing bool, n int) {
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		closing = true
+		i++
+	}
+	start := i
+	for i < len(s) && isTagNameChar(s[i], i == start) {
+		i++
+	}
+	if i == start && !(i < len(s) && s[i] == '!' && !closing) {
+		return "", false, 0
+	}
+	name = strings.ToLower(s[start:i])
+
+	// Skip attributes, respecting quoted values that may contain ">"
+	var quote byte
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return name, closing, i + 1
+		}
+	}
+	// Unterminated tag: drop the rest of the input
+	return name, closing, len(s)
+}
+
+// isTagNameChar reports whether c may appear in the name of a tag. The
+// first character must be a letter.
+func isTagNameChar(c byte, first bool) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9', c == '-', c == ':':
+		return !first
+	}
+	return false
+}
+
+// StripHTML removes HTML from the description of the product with the
+// package-level StripHTML function before it is sent to the server, e.g.
+// when importing descriptions from a web shop. It modifies the product.
+func (s *CreateService) StripHTML() *CreateService {
+	s.stripHTML = true
+	return s
+}
+
+// StripHTML removes HTML from the description of the product with the
+// package-level StripHTML function before it is sent to the server, e.g.
+// when importing descriptions from a web shop. It modifies the product.
+func (s *UpsertService) StripHTML() *UpsertService {
+	s.stripHTML = true
+	return s
+}
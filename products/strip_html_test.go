@@ -0,0 +1,100 @@
+package products_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		In   string
+		Want string
+	}{
+		{"", ""},
+		{"Plain text", "Plain text"},
+		{"<b>Bold</b> and <i>italic</i>", "Bold and italic"},
+		{"<div><p>Nested <span><b>tags</b></span></p><p>Second</p></div>", "Nested tags\nSecond"},
+		{"<ul>\n  <li>One</li>\n  <li>Two</li>\n</ul>", "One\nTwo"},
+		{"Line 1<br>Line 2<br/>Line 3<BR />", "Line 1\nLine 2\nLine 3"},
+		{"Fish &amp; Chips &lt;b&gt; &uuml;ber &#8364;5 &euro;", "Fish & Chips <b> über €5 €"},
+		{"A&nbsp;&nbsp;B", "A B"},
+		{`<a href="x>y" title='a > b'>Link</a>`, "Link"},
+		{"Before<!-- <p>comment</p> -->After", "BeforeAfter"},
+		{"<style>p { color: red; }</style><script>if (a < b) {}</script>Text", "Text"},
+		{"<!DOCTYPE html><html><body>Body</body></html>", "Body"},
+		{"1 < 2 and 3 > 2", "1 < 2 and 3 > 2"},
+		{"Open <b", "Open"},
+		{"   lots   of \t whitespace  ", "lots of whitespace"},
+	}
+	for i, test := range tests {
+		if got := products.StripHTML(test.In); got != test.Want {
+			t.Errorf("#%d: expected %q; got: %q", i, test.Want, got)
+		}
+	}
+}
+
+func TestProductCreateStripHTML(t *testing.T) {
+	var sent products.CreateProduct
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+		}
+		return "products.create.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := &products.CreateProduct{
+		Spn:         "1000",
+		Name:        "Produkt 1000",
+		Price:       4.99,
+		OrderUnit:   "PCE",
+		Description: "<p>Very <b>good</b> &amp; cheap</p>",
+	}
+	if _, err := service.Create().PIN("AD8CCDD5F9").Area("work").Product(p).StripHTML().Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Very good & cheap"; sent.Description != want {
+		t.Fatalf("expected description %q; got: %q", want, sent.Description)
+	}
+	if want := "<p>Very <b>good</b> &amp; cheap</p>"; p.Description != want {
+		t.Fatalf("expected product of the caller to be unchanged; got: %q", p.Description)
+	}
+}
+
+func TestProductUpsertStripHTML(t *testing.T) {
+	var sent products.UpsertProduct
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+		}
+		return "products.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := &products.UpsertProduct{
+		Spn:         "1000",
+		Name:        "Produkt 1000",
+		Price:       4.99,
+		OrderUnit:   "PCE",
+		Description: "<p>Very <b>good</b></p>",
+	}
+	if _, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).StripHTML().Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Very good"; sent.Description != want {
+		t.Fatalf("expected description %q; got: %q", want, sent.Description)
+	}
+	if want := "<p>Very <b>good</b></p>"; p.Description != want {
+		t.Fatalf("expected product of the caller to be unchanged; got: %q", p.Description)
+	}
+}
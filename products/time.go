@@ -0,0 +1,25 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// UnmarshalJSON decodes a product. Created and Updated are parsed
+// tolerantly, i.e. they may have other formats than RFC 3339 as well, e.g.
+// no fractional seconds or no time zone.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type product Product
+	return meplatoapi.UnmarshalTimes(data, (*product)(p))
+}
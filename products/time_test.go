@@ -0,0 +1,44 @@
+package products_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestProductUnmarshalTimes(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  time.Time
+	}{
+		{"2015-03-31T14:54:37Z", time.Date(2015, 3, 31, 14, 54, 37, 0, time.UTC)},
+		{"2015-03-31T14:54:37.123456Z", time.Date(2015, 3, 31, 14, 54, 37, 123456000, time.UTC)},
+		{"2015-03-31T14:54:37", time.Date(2015, 3, 31, 14, 54, 37, 0, time.UTC)},
+		{"2015-03-31 16:54:37+0200", time.Date(2015, 3, 31, 14, 54, 37, 0, time.UTC)},
+		{"2015-03-31", time.Date(2015, 3, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, tt := range tests {
+		var p products.Product
+		data := `{"spn":"1000","price":4.99,"created":"` + tt.Input + `","updated":"` + tt.Input + `"}`
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			t.Errorf("%d. expected %q to decode; got: %v", i, tt.Input, err)
+			continue
+		}
+		if p.Spn != "1000" || p.Price != 4.99 {
+			t.Errorf("%d. expected other fields to be decoded; got: %+v", i, p)
+		}
+		if p.Created == nil || !p.Created.Equal(tt.Want) {
+			t.Errorf("%d. expected created %v; got: %v", i, tt.Want, p.Created)
+		}
+		if p.Updated == nil || !p.Updated.Equal(tt.Want) {
+			t.Errorf("%d. expected updated %v; got: %v", i, tt.Want, p.Updated)
+		}
+	}
+
+	var p products.Product
+	if err := json.Unmarshal([]byte(`{"created":"last week"}`), &p); err == nil {
+		t.Error("expected error for invalid time; got: nil")
+	}
+}
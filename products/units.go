@@ -0,0 +1,128 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnitCodes are the order and content unit codes accepted by ValidateUnits,
+// mapped to their meaning. They are common codes of UN/ECE Recommendation
+// 20. Unit codes may be project-specific, so add the codes your projects
+// use before validating, e.g. UnitCodes["BOX"] = "box".
+var UnitCodes = map[string]string{
+	// Counts
+	"PCE": "piece",
+	"H87": "piece",
+	"C62": "one",
+	"SET": "set",
+	"NPR": "number of pairs",
+	"NMP": "number of packs",
+	"NAR": "number of articles",
+	"DZN": "dozen",
+	"GRO": "gross",
+	// Weight
+	"MGM": "milligram",
+	"GRM": "gram",
+	"KGM": "kilogram",
+	"TNE": "tonne",
+	// Length and area
+	"MMT": "millimetre",
+	"CMT": "centimetre",
+	"MTR": "metre",
+	"KMT": "kilometre",
+	"MTK": "square metre",
+	// Volume
+	"MLT": "millilitre",
+	"CLT": "centilitre",
+	"LTR": "litre",
+	"MTQ": "cubic metre",
+	// Time
+	"MIN": "minute",
+	"HUR": "hour",
+	"DAY": "day",
+	"WEE": "week",
+	"MON": "month",
+	"ANN": "year",
+	// Energy
+	"KWH": "kilowatt hour",
+}
+
+// ValidateUnitCode checks that code is a unit code, i.e. 3 uppercase
+// letters or digits, listed in UnitCodes.
+func ValidateUnitCode(code string) error {
+	if len(code) != 3 {
+		return fmt.Errorf("%q must have 3 characters", code)
+	}
+	for _, c := range code {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return fmt.Errorf("%q must consist of uppercase letters and digits", code)
+		}
+	}
+	if _, ok := UnitCodes[code]; !ok {
+		return fmt.Errorf("%q is not a known unit code", code)
+	}
+	return nil
+}
+
+// ValidateUnits checks the order unit and, if set, the content unit of
+// product p, which must be a *CreateProduct or an *UpsertProduct, with
+// ValidateUnitCode. It returns nil if both are valid and ValidationErrors
+// otherwise.
+func ValidateUnits(p interface{}) error {
+	var product *UpsertProduct
+	switch p := p.(type) {
+	case *UpsertProduct:
+		product = p
+	case *CreateProduct:
+		product = (*UpsertProduct)(p)
+	default:
+		return fmt.Errorf("products: cannot validate %T", p)
+	}
+	if product == nil {
+		return ValidationErrors{{Field: "product", Message: "is missing"}}
+	}
+
+	var errs ValidationErrors
+	if strings.TrimSpace(product.OrderUnit) == "" {
+		errs = append(errs, &ValidationError{Field: "ou", Message: "is required"})
+	} else if err := ValidateUnitCode(product.OrderUnit); err != nil {
+		errs = append(errs, &ValidationError{Field: "ou", Message: err.Error()})
+	}
+	if product.ContentUnit != "" {
+		if err := ValidateUnitCode(product.ContentUnit); err != nil {
+			errs = append(errs, &ValidationError{Field: "cu", Message: err.Error()})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateUnits validates the order and content unit of the product with
+// ValidateUnits before sending it to the server. If a unit is invalid, Do
+// returns the ValidationErrors without issuing a request.
+func (s *CreateService) ValidateUnits() *CreateService {
+	s.validateUnits = true
+	return s
+}
+
+// ValidateUnits validates the order and content unit of the product with
+// ValidateUnits before sending it to the server. If a unit is invalid, Do
+// returns the ValidationErrors without issuing a request.
+func (s *UpsertService) ValidateUnits() *UpsertService {
+	s.validateUnits = true
+	return s
+}
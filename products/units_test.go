@@ -0,0 +1,85 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestValidateUnitCode(t *testing.T) {
+	tests := []struct {
+		Code  string
+		Valid bool
+	}{
+		{"PCE", true},
+		{"C62", true},
+		{"KGM", true},
+		{"PCS", false},
+		{"pce", false},
+		{"piece", false},
+		{"PC", false},
+		{"", false},
+	}
+	for i, tt := range tests {
+		err := products.ValidateUnitCode(tt.Code)
+		if tt.Valid && err != nil {
+			t.Errorf("%d. expected %q to be valid; got: %v", i, tt.Code, err)
+		}
+		if !tt.Valid && err == nil {
+			t.Errorf("%d. expected %q to be invalid", i, tt.Code)
+		}
+	}
+}
+
+func TestValidateUnits(t *testing.T) {
+	p := &products.CreateProduct{Spn: "MBA11", Name: "Apple MacBook Air 11\"", OrderUnit: "PCE", ContentUnit: "piece"}
+	err := products.ValidateUnits(p)
+	errs, ok := err.(products.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	if len(errs) != 1 || errs[0].Field != "cu" {
+		t.Fatalf("expected an error for cu; got: %v", errs)
+	}
+
+	p.ContentUnit = ""
+	if err := products.ValidateUnits(p); err != nil {
+		t.Fatalf("expected no error without content unit; got: %v", err)
+	}
+}
+
+func TestProductCreateValidateUnits(t *testing.T) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		return "products.create.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := &products.CreateProduct{Spn: "MBA11", Name: "Apple MacBook Air 11\"", Price: 1299.00, OrderUnit: "PCS"}
+	_, err = service.Create().PIN("AD8CCDD5F9").Area("work").Product(p).ValidateUnits().Do(context.Background())
+	if _, ok := err.(products.ValidationErrors); !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no request to be sent; got: %d", requests)
+	}
+
+	// Without ValidateUnits, the product is sent as is
+	if _, err = service.Create().PIN("AD8CCDD5F9").Area("work").Product(p).Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	p.OrderUnit = "PCE"
+	if _, err = service.Create().PIN("AD8CCDD5F9").Area("work").Product(p).ValidateUnits().Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected %d requests; got: %d", 2, requests)
+	}
+}
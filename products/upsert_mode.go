@@ -0,0 +1,182 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// UpsertMode restricts what an upsert may do, see UpsertService.Mode.
+type UpsertMode int
+
+const (
+	// UpsertCreateOrUpdate creates the product if it does not exist and
+	// updates it otherwise (default).
+	UpsertCreateOrUpdate UpsertMode = iota
+	// UpsertCreateOnly only creates the product. It fails with a
+	// *ConflictError if the product already exists.
+	UpsertCreateOnly
+	// UpsertUpdateOnly only updates the product. It fails with a
+	// *ConflictError if the product does not exist.
+	UpsertUpdateOnly
+)
+
+func (m UpsertMode) String() string {
+	switch m {
+	case UpsertCreateOrUpdate:
+		return "create or update"
+	case UpsertCreateOnly:
+		return "create only"
+	case UpsertUpdateOnly:
+		return "update only"
+	}
+	return fmt.Sprintf("UpsertMode(%d)", int(m))
+}
+
+// ConflictError is returned by an upsert in UpsertCreateOnly mode if the
+// product exists, and in UpsertUpdateOnly mode if it does not exist.
+// Nothing has been written in that case.
+type ConflictError struct {
+	// Mode is the mode of the upsert.
+	Mode UpsertMode
+	// Spn is the SPN of the product.
+	Spn string
+	// Err is the error returned by the server, if any.
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	if e.Mode == UpsertUpdateOnly {
+		return fmt.Sprintf("products: product %q does not exist", e.Spn)
+	}
+	return fmt.Sprintf("products: product %q already exists", e.Spn)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Mode restricts the upsert to create or update only, e.g. to prevent
+// accidental overwrites during partial imports. The API has no such
+// option for upserts, so the mode is enforced by the client: Do looks up
+// the product first and returns a *ConflictError if the mode does not
+// allow to write it. Otherwise, it creates the product in
+// UpsertCreateOnly mode, and replaces it in UpsertUpdateOnly mode. A
+// product that is deleted between both requests makes the replace fail
+// with a *ConflictError as well.
+func (s *UpsertService) Mode(mode UpsertMode) *UpsertService {
+	s.mode = mode
+	return s
+}
+
+// doMode upserts p in UpsertCreateOnly or UpsertUpdateOnly mode.
+func (s *UpsertService) doMode(ctx context.Context, p *UpsertProduct) (*UpsertProductResponse, error) {
+	var spn string
+	if p != nil {
+		spn = p.Spn
+	}
+	get := s.s.Get().PIN(s.pin).Area(s.area).Spn(spn)
+	s.copyHeaders(get.hdr_, "Authorization")
+	_, err := get.Do(ctx)
+	switch {
+	case err == nil && s.mode == UpsertCreateOnly:
+		return nil, &ConflictError{Mode: s.mode, Spn: spn}
+	case isNotFound(err) && s.mode == UpsertUpdateOnly:
+		return nil, &ConflictError{Mode: s.mode, Spn: spn, Err: err}
+	case err != nil && !isNotFound(err):
+		return nil, err
+	}
+
+	if s.mode == UpsertCreateOnly {
+		product := new(CreateProduct)
+		if err := copyProduct(p, product); err != nil {
+			return nil, err
+		}
+		create := s.s.Create().PIN(s.pin).Area(s.area).Product(product)
+		s.copyHeaders(create.hdr_, "Authorization", "Prefer")
+		create.minimal = s.minimal
+		res, err := create.Do(ctx)
+		if err != nil {
+			return nil, s.conflict(err)
+		}
+		return &UpsertProductResponse{
+			Kind:     res.Kind,
+			Link:     res.Link,
+			Warnings: res.Warnings,
+			Location: res.Location,
+			JobID:    res.JobID,
+		}, nil
+	}
+
+	product := new(ReplaceProduct)
+	if err := copyProduct(p, product); err != nil {
+		return nil, err
+	}
+	replace := s.s.Replace().PIN(s.pin).Area(s.area).Spn(spn).Product(product)
+	s.copyHeaders(replace.hdr_, "Authorization", "Prefer")
+	replace.minimal = s.minimal
+	res, err := replace.Do(ctx)
+	if err != nil {
+		return nil, s.conflict(err)
+	}
+	return &UpsertProductResponse{Kind: res.Kind, Link: res.Link, Warnings: res.Warnings}, nil
+}
+
+// copyHeaders copies the given headers of s, if set, to hdr.
+func (s *UpsertService) copyHeaders(hdr map[string]interface{}, keys ...string) {
+	for _, k := range keys {
+		if v, ok := s.hdr_[k]; ok {
+			hdr[k] = v
+		}
+	}
+}
+
+// copyProduct copies the fields of the upsert product p into dst, which
+// must be a pointer to CreateProduct or ReplaceProduct. Fields are matched
+// by their JSON names.
+func copyProduct(p *UpsertProduct, dst interface{}) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("products: cannot convert product: %v", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("products: cannot convert product: %v", err)
+	}
+	return nil
+}
+
+// conflict returns a *ConflictError if err reports that the product
+// exists in UpsertCreateOnly mode or has been deleted in UpsertUpdateOnly
+// mode, and err otherwise.
+func (s *UpsertService) conflict(err error) error {
+	var e *meplatoapi.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	switch {
+	case e.Code == http.StatusConflict && s.mode == UpsertCreateOnly,
+		e.Code == http.StatusNotFound && s.mode == UpsertUpdateOnly:
+		var spn string
+		if s.product != nil {
+			spn = s.product.Spn
+		}
+		return &ConflictError{Mode: s.mode, Spn: spn, Err: err}
+	}
+	return err
+}
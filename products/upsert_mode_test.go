@@ -0,0 +1,104 @@
+package products_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+// getUpsertModeService returns a service whose product exists if exists
+// is true, along with the requests sent to it, e.g. "GET .../products/1".
+func getUpsertModeService(t *testing.T, exists bool) (*products.Service, *[]string, func()) {
+	var requests []string
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/catalogs/AD8CCDD5F9/work"))
+		switch r.Method {
+		case "GET":
+			if exists {
+				return "products.get.success"
+			}
+			return "products.get.not_found"
+		case "PUT":
+			return "products.replace.success"
+		}
+		if strings.HasSuffix(r.URL.Path, "/upsert") {
+			return "products.upsert.success"
+		}
+		return "products.create.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, &requests, ts.Close
+}
+
+func TestProductUpsertMode(t *testing.T) {
+	p := products.ExampleUpsertProduct()
+	tests := []struct {
+		mode     products.UpsertMode
+		exists   bool
+		conflict bool
+		requests []string
+	}{
+		{products.UpsertCreateOrUpdate, true, false, []string{"POST /products/upsert"}},
+		{products.UpsertCreateOnly, false, false, []string{"GET /products/" + p.Spn, "POST /products"}},
+		{products.UpsertCreateOnly, true, true, []string{"GET /products/" + p.Spn}},
+		{products.UpsertUpdateOnly, true, false, []string{"GET /products/" + p.Spn, "PUT /products/" + p.Spn}},
+		{products.UpsertUpdateOnly, false, true, []string{"GET /products/" + p.Spn}},
+	}
+	for _, tt := range tests {
+		service, requests, closer := getUpsertModeService(t, tt.exists)
+		res, err := service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).Mode(tt.mode).Do(context.Background())
+		closer()
+
+		var conflict *products.ConflictError
+		if tt.conflict {
+			if !errors.As(err, &conflict) {
+				t.Errorf("%v (exists=%v): expected *ConflictError; got: %T (%v)", tt.mode, tt.exists, err, err)
+			} else if conflict.Mode != tt.mode || conflict.Spn != p.Spn {
+				t.Errorf("%v (exists=%v): expected conflict for %q; got: %q in mode %v", tt.mode, tt.exists, p.Spn, conflict.Spn, conflict.Mode)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("%v (exists=%v): expected no error; got: %v", tt.mode, tt.exists, err)
+			} else if res == nil || res.Link == "" {
+				t.Errorf("%v (exists=%v): expected response with link; got: %+v", tt.mode, tt.exists, res)
+			}
+		}
+		if !reflect.DeepEqual(*requests, tt.requests) {
+			t.Errorf("%v (exists=%v): expected requests %q; got: %q", tt.mode, tt.exists, tt.requests, *requests)
+		}
+	}
+}
+
+func TestProductUpsertModeDeleted(t *testing.T) {
+	// The product is deleted after it has been looked up
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		if r.Method == "GET" {
+			return "products.get.success"
+		}
+		return "products.get.not_found"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := products.ExampleUpsertProduct()
+	_, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).Mode(products.UpsertUpdateOnly).Do(context.Background())
+	var conflict *products.ConflictError
+	if !errors.As(err, &conflict) || conflict.Mode != products.UpsertUpdateOnly {
+		t.Fatalf("expected *ConflictError in update only mode; got: %T (%v)", err, err)
+	}
+
+	// Without a mode, errors are returned as is
+	_, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).Do(context.Background())
+	if err == nil || errors.As(err, &conflict) {
+		t.Fatalf("expected server error without mode; got: %v", err)
+	}
+}
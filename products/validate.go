@@ -0,0 +1,119 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Targets of a catalog, see the Target field of a catalog.
+const (
+	TargetDefault  = ""
+	TargetCatscout = "catscout"
+	TargetMall     = "mall"
+)
+
+// ValidationError describes a single problem with a product that has been
+// found by validating it locally.
+type ValidationError struct {
+	// Field is the JSON name of the field, e.g. "image".
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("products: %s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors is a list of problems found by validating a product.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// targetRequirements lists the fields that are required in addition to
+// the basic ones for a certain catalog target.
+var targetRequirements = map[string][]struct {
+	field   string
+	present func(p *UpsertProduct) bool
+}{
+	TargetCatscout: {
+		{"image", func(p *UpsertProduct) bool { return strings.TrimSpace(p.Image) != "" }},
+		{"datasheet", func(p *UpsertProduct) bool { return strings.TrimSpace(p.Datasheet) != "" }},
+	},
+}
+
+// ValidateForTarget checks product p, which must be a *CreateProduct or
+// an *UpsertProduct, against the requirements of a catalog with the given
+// target, e.g. TargetCatscout. It returns nil if p is valid and
+// ValidationErrors otherwise. Use it to check products before sending
+// them to the server.
+func ValidateForTarget(p interface{}, target string) error {
+	var product *UpsertProduct
+	switch p := p.(type) {
+	case *UpsertProduct:
+		product = p
+	case *CreateProduct:
+		product = (*UpsertProduct)(p)
+	default:
+		return fmt.Errorf("products: cannot validate %T", p)
+	}
+	if product == nil {
+		return ValidationErrors{{Field: "product", Message: "is missing"}}
+	}
+
+	var errs ValidationErrors
+	if strings.TrimSpace(product.Spn) == "" {
+		errs = append(errs, &ValidationError{Field: "spn", Message: "is required"})
+	}
+	if strings.TrimSpace(product.Name) == "" {
+		errs = append(errs, &ValidationError{Field: "name", Message: "is required"})
+	}
+	if strings.TrimSpace(product.OrderUnit) == "" {
+		errs = append(errs, &ValidationError{Field: "ou", Message: "is required"})
+	}
+	for _, req := range targetRequirements[target] {
+		if !req.present(product) {
+			errs = append(errs, &ValidationError{Field: req.field, Message: fmt.Sprintf("is required for target %q", target)})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateTarget validates the product with ValidateForTarget before
+// sending it to the server. If the product is invalid, Do returns the
+// ValidationErrors without issuing a request.
+func (s *CreateService) ValidateTarget(target string) *CreateService {
+	s.validate = true
+	s.target = target
+	return s
+}
+
+// ValidateTarget validates the product with ValidateForTarget before
+// sending it to the server. If the product is invalid, Do returns the
+// ValidationErrors without issuing a request.
+func (s *UpsertService) ValidateTarget(target string) *UpsertService {
+	s.validate = true
+	s.target = target
+	return s
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ProductReport lists the problems of a single product found by
+// ValidateCatalog.
+type ProductReport struct {
+	// Spn is the supplier part number of the product.
+	Spn string
+	// Errors are the problems found.
+	Errors ValidationErrors
+}
+
+// CatalogReport is the result of ValidateCatalog.
+type CatalogReport struct {
+	// Products is the number of products that have been checked.
+	Products int
+	// Invalid lists the products with problems, in the order they have
+	// been scrolled.
+	Invalid []*ProductReport
+}
+
+// Valid reports whether no problems have been found.
+func (r *CatalogReport) Valid() bool {
+	return len(r.Invalid) == 0
+}
+
+// ValidateCatalog scrolls through all products in the given area of the
+// catalog and checks each with ValidateProduct. It returns a report of
+// the products with problems. Use it to find problems locally before
+// publishing a catalog. An error is returned only if scrolling fails.
+func ValidateCatalog(ctx context.Context, service *Service, pin, area string) (*CatalogReport, error) {
+	report := new(CatalogReport)
+	err := service.Scroll().PIN(pin).Area(area).Pages(ctx, func(res *ScrollResponse) error {
+		for _, p := range res.Items {
+			report.Products++
+			if err := ValidateProduct(p); err != nil {
+				report.Invalid = append(report.Invalid, &ProductReport{Spn: p.Spn, Errors: err.(ValidationErrors)})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ValidateProduct checks product p for problems that can be found
+// locally: missing required fields, a negative price, a GTIN with an
+// invalid check digit, a malformed currency code, and incomplete or
+// malformed eCl@ss and UNSPSC classifications. It returns nil if p is
+// valid and ValidationErrors otherwise.
+func ValidateProduct(p *Product) error {
+	if p == nil {
+		return ValidationErrors{{Field: "product", Message: "is missing"}}
+	}
+
+	var errs ValidationErrors
+	if strings.TrimSpace(p.Spn) == "" {
+		errs = append(errs, &ValidationError{Field: "spn", Message: "is required"})
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		errs = append(errs, &ValidationError{Field: "name", Message: "is required"})
+	}
+	if strings.TrimSpace(p.OrderUnit) == "" {
+		errs = append(errs, &ValidationError{Field: "ou", Message: "is required"})
+	}
+	if p.Price < 0 {
+		errs = append(errs, &ValidationError{Field: "price", Message: "must not be negative"})
+	}
+	if p.Gtin != "" && !isValidGTIN(p.Gtin) {
+		errs = append(errs, &ValidationError{Field: "gtin", Message: fmt.Sprintf("%q is not a valid GTIN", p.Gtin)})
+	}
+	if p.Currency != "" && !isCurrencyCode(p.Currency) {
+		errs = append(errs, &ValidationError{Field: "currency", Message: fmt.Sprintf("%q is not an ISO currency code", p.Currency)})
+	}
+	for i, e := range p.Eclasses {
+		field := fmt.Sprintf("eclasses[%d]", i)
+		switch {
+		case e == nil:
+			errs = append(errs, &ValidationError{Field: field, Message: "is empty"})
+		case strings.TrimSpace(e.Version) == "":
+			errs = append(errs, &ValidationError{Field: field, Message: "has no version"})
+		case !isEclassCode(e.Code):
+			errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf("%q is not an eCl@ss code", e.Code)})
+		}
+	}
+	for i, u := range p.Unspscs {
+		field := fmt.Sprintf("unspscs[%d]", i)
+		switch {
+		case u == nil:
+			errs = append(errs, &ValidationError{Field: field, Message: "is empty"})
+		case strings.TrimSpace(u.Version) == "":
+			errs = append(errs, &ValidationError{Field: field, Message: "has no version"})
+		case !isUnspscCode(u.Code):
+			errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf("%q is not a UNSPSC code", u.Code)})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// isValidGTIN reports whether gtin is a GTIN-8, -12, -13, or -14 with a
+// valid check digit.
+func isValidGTIN(gtin string) bool {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	var sum int
+	for i := len(gtin) - 1; i >= 0; i-- {
+		c := gtin[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if i == len(gtin)-1 {
+			continue
+		}
+		if (len(gtin)-1-i)%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	check := (10 - sum%10) % 10
+	return check == int(gtin[len(gtin)-1]-'0')
+}
+
+// isCurrencyCode reports whether s looks like an ISO 4217 currency code,
+// e.g. EUR.
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isEclassCode reports whether s is an eCl@ss code with 8 digits, with or
+// without dashes, e.g. 19-01-01-01 or 19010101.
+func isEclassCode(s string) bool {
+	var digits int
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '-':
+		default:
+			return false
+		}
+	}
+	return digits == 8
+}
+
+// isUnspscCode reports whether s is a UNSPSC code with 8 digits, e.g.
+// 43211503, or 10 digits including the business function.
+func isUnspscCode(s string) bool {
+	if len(s) != 8 && len(s) != 10 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package products_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestValidateProduct(t *testing.T) {
+	valid := &products.Product{Spn: "MBA11", Name: "MacBook Air", OrderUnit: "PCE", Price: 1299, Currency: "EUR"}
+	for _, gtin := range []string{"", "4006381333931", "96385074", "036000291452", "00036000291452"} {
+		p := *valid
+		p.Gtin = gtin
+		if err := products.ValidateProduct(&p); err != nil {
+			t.Errorf("GTIN %q: expected valid product; got: %v", gtin, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(p *products.Product)
+		field string
+	}{
+		{"no SPN", func(p *products.Product) { p.Spn = " " }, "spn"},
+		{"no name", func(p *products.Product) { p.Name = "" }, "name"},
+		{"no order unit", func(p *products.Product) { p.OrderUnit = "" }, "ou"},
+		{"negative price", func(p *products.Product) { p.Price = -1 }, "price"},
+		{"wrong check digit", func(p *products.Product) { p.Gtin = "4006381333932" }, "gtin"},
+		{"wrong length", func(p *products.Product) { p.Gtin = "400638133393" }, "gtin"},
+		{"letters in GTIN", func(p *products.Product) { p.Gtin = "40063813339A" }, "gtin"},
+		{"lowercase currency", func(p *products.Product) { p.Currency = "eur" }, "currency"},
+		{"long currency", func(p *products.Product) { p.Currency = "EURO" }, "currency"},
+		{"eCl@ss without version", func(p *products.Product) {
+			p.Eclasses = []*products.Eclass{{Code: "19010101"}}
+		}, "eclasses[0]"},
+		{"malformed eCl@ss code", func(p *products.Product) {
+			p.Eclasses = []*products.Eclass{{Version: "5.1", Code: "19-01-01-01"}, {Version: "5.1", Code: "19-01"}}
+		}, "eclasses[1]"},
+		{"UNSPSC without version", func(p *products.Product) {
+			p.Unspscs = []*products.Unspsc{{Code: "43211503"}}
+		}, "unspscs[0]"},
+		{"UNSPSC code with dashes", func(p *products.Product) {
+			p.Unspscs = []*products.Unspsc{{Version: "16.0901", Code: "4321150310"}, {Version: "16.0901", Code: "43-21-15-03"}}
+		}, "unspscs[1]"},
+		{"short UNSPSC code", func(p *products.Product) {
+			p.Unspscs = []*products.Unspsc{{Version: "16.0901", Code: "432115"}}
+		}, "unspscs[0]"},
+	}
+	for _, tt := range tests {
+		p := *valid
+		tt.edit(&p)
+		err := products.ValidateProduct(&p)
+		var errs products.ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("%s: expected ValidationErrors; got: %v", tt.name, err)
+			continue
+		}
+		if len(errs) != 1 || errs[0].Field != tt.field {
+			t.Errorf("%s: expected a single error for %q; got: %v", tt.name, tt.field, errs)
+		}
+	}
+}
+
+func TestValidateCatalog(t *testing.T) {
+	service, ts, err := getService("products.scroll.invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	report, err := products.ValidateCatalog(context.Background(), service, "AD8CCDD5F9", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Products != 3 {
+		t.Fatalf("expected %d products; got: %d", 3, report.Products)
+	}
+	if report.Valid() {
+		t.Fatal("expected catalog to be invalid")
+	}
+	if len(report.Invalid) != 2 {
+		t.Fatalf("expected %d invalid products; got: %d", 2, len(report.Invalid))
+	}
+	if r := report.Invalid[0]; r.Spn != "MBA13" || len(r.Errors) != 2 {
+		t.Errorf("expected GTIN and currency errors for MBA13; got: %s %v", r.Spn, r.Errors)
+	}
+	if r := report.Invalid[1]; r.Spn != "MBP15" || len(r.Errors) != 4 {
+		t.Errorf("expected name, order unit, and two eCl@ss errors for MBP15; got: %s %v", r.Spn, r.Errors)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package products
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// ValidationIssue is a single problem of a product in a CatalogReport.
+type ValidationIssue struct {
+	// Spn is the supplier part number of the product.
+	Spn string `json:"spn"`
+	// Field is the JSON name of the field, e.g. "gtin".
+	Field string `json:"field"`
+	// Issue describes the problem.
+	Issue string `json:"issue"`
+}
+
+// Issues returns the problems of all invalid products, one per field, in
+// the order of Invalid.
+func (r *CatalogReport) Issues() []*ValidationIssue {
+	var issues []*ValidationIssue
+	for _, p := range r.Invalid {
+		for _, e := range p.Errors {
+			issues = append(issues, &ValidationIssue{Spn: p.Spn, Field: e.Field, Issue: e.Message})
+		}
+	}
+	return issues
+}
+
+// WriteCSV writes the issues of the report to w, one per line, with the
+// columns SPN, Field, and Issue. Like the files of the store command, the
+// columns are separated by a semicolon. A valid catalog results in the
+// header line only.
+func (r *CatalogReport) WriteCSV(w io.Writer) error {
+	csvw := csv.NewWriter(w)
+	csvw.Comma = ';'
+	csvw.UseCRLF = true
+	if err := csvw.Write([]string{"SPN", "Field", "Issue"}); err != nil {
+		return err
+	}
+	for _, issue := range r.Issues() {
+		if err := csvw.Write([]string{issue.Spn, issue.Field, issue.Issue}); err != nil {
+			return err
+		}
+	}
+	csvw.Flush()
+	return csvw.Error()
+}
+
+// WriteJSON writes the report to w as a JSON object with the number of
+// checked products and the list of issues, e.g.
+// {"products":3,"issues":[{"spn":"MBA13","field":"gtin","issue":"..."}]}.
+func (r *CatalogReport) WriteJSON(w io.Writer) error {
+	issues := r.Issues()
+	if issues == nil {
+		issues = []*ValidationIssue{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Products int                `json:"products"`
+		Issues   []*ValidationIssue `json:"issues"`
+	}{r.Products, issues})
+}
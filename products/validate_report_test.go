@@ -0,0 +1,65 @@
+package products_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestCatalogReportWrite(t *testing.T) {
+	service, ts, err := getService("products.scroll.invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	report, err := products.ValidateCatalog(context.Background(), service, "AD8CCDD5F9", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\r\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected header and %d issues; got: %q", 6, lines)
+	}
+	if lines[0] != "SPN;Field;Issue" {
+		t.Errorf("expected header %q; got: %q", "SPN;Field;Issue", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "MBA13;gtin;") {
+		t.Errorf("expected GTIN issue of MBA13 first; got: %q", lines[1])
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Products int                         `json:"products"`
+		Issues   []*products.ValidationIssue `json:"issues"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Products != 3 || len(doc.Issues) != 6 {
+		t.Fatalf("expected 3 products and 6 issues; got: %d and %d", doc.Products, len(doc.Issues))
+	}
+	if got := doc.Issues[5]; got.Spn != "MBP15" || got.Field == "" || got.Issue == "" {
+		t.Errorf("expected issue of MBP15 last; got: %+v", got)
+	}
+
+	buf.Reset()
+	if err := new(products.CatalogReport).WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"issues": []`) {
+		t.Errorf("expected empty list of issues; got: %s", buf.String())
+	}
+}
@@ -0,0 +1,71 @@
+package products_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+func TestValidateForTarget(t *testing.T) {
+	p := products.ExampleUpsertProduct()
+	p.Datasheet = ""
+	if err := products.ValidateForTarget(p, products.TargetDefault); err != nil {
+		t.Fatalf("expected no error for default target; got: %v", err)
+	}
+	err := products.ValidateForTarget(p, products.TargetCatscout)
+	errs, ok := err.(products.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	if len(errs) != 1 || errs[0].Field != "datasheet" {
+		t.Fatalf("expected a single error for field %q; got: %v", "datasheet", errs)
+	}
+
+	c := products.ExampleCreateProduct()
+	c.Name = ""
+	c.Image = ""
+	err = products.ValidateForTarget(c, products.TargetCatscout)
+	errs, ok = err.(products.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	if len(fields) != 2 || fields[0] != "name" || fields[1] != "image" {
+		t.Fatalf("expected errors for name and image; got: %v", fields)
+	}
+}
+
+func TestProductUpsertValidateTarget(t *testing.T) {
+	var requests int
+	service, ts, err := getServiceWithRoutes(func(r *http.Request) string {
+		requests++
+		return "products.upsert.success"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	p := products.ExampleUpsertProduct()
+	p.Image = ""
+	_, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).ValidateTarget(products.TargetCatscout).Do(context.Background())
+	if _, ok := err.(products.ValidationErrors); !ok {
+		t.Fatalf("expected ValidationErrors; got: %T (%v)", err, err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no request to be sent; got: %d", requests)
+	}
+
+	p.Image = "mba11.jpg"
+	if _, err = service.Upsert().PIN("AD8CCDD5F9").Area("work").Product(p).ValidateTarget(products.TargetCatscout).Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected %d request; got: %d", 1, requests)
+	}
+}
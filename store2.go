@@ -18,15 +18,13 @@ package store2
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
-	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -63,32 +61,156 @@ type Service struct {
 	BaseURL  string
 	User     string
 	Password string
+	// MaxRetries is the number of times a request is retried on network
+	// errors and transient server errors like 503 (default 0).
+	MaxRetries int
+	// Debug logs every request and response, including the indented JSON
+	// bodies, to Logger. The Authorization header is redacted.
+	Debug bool
+	// Logger is used in debug mode. If nil, the standard logger is used.
+	Logger Logger
+	// MaxResponseBytes limits the size of response bodies. Reading a larger
+	// body fails with an error that matches store2.ErrResponseTooLarge
+	// (default 0, i.e. unlimited).
+	MaxResponseBytes int64
+	// Trace, if set, creates a ClientTrace for every request, e.g. to
+	// measure DNS, connect, TLS, and time-to-first-byte timings. See
+	// store2.TimingTrace for a ready-made implementation.
+	Trace func(req *http.Request) *httptrace.ClientTrace
+	// ContextHeaders maps context keys to the names of HTTP headers. If
+	// the context of a request has a value for a key, e.g. a tenant or
+	// correlation ID stored by middleware, it is sent in the mapped header
+	// without passing it to every operation. Values are formatted with
+	// fmt.Sprint. Headers set by an operation take precedence.
+	ContextHeaders map[interface{}]string
+	// ObserveAttempts, if set, is called after every request with the
+	// number of attempts it took, i.e. 1 if it was not retried, e.g. to
+	// record retries in your metrics and alert when the backend is flaky.
+	ObserveAttempts func(req *http.Request, attempts int)
+
+	limiter    meplatoapi.Limiter
+	middleware []Middleware
 }
 
+// Logger is used to log requests and responses in debug mode. It is
+// implemented by *log.Logger.
+type Logger = meplatoapi.Logger
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc = meplatoapi.RoundTripFunc
+
+// Middleware wraps the RoundTripFunc of a service. See Service.Use.
+type Middleware = meplatoapi.Middleware
+
 func New(client *http.Client) (*Service, error) {
 	if client == nil {
-		client = &http.Client{
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   30 * time.Second,
-					KeepAlive: 30 * time.Second,
-					DualStack: true,
-				}).DialContext,
-				MaxIdleConns:          100,
-				IdleConnTimeout:       90 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-				MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
-				TLSClientConfig: &tls.Config{
-					MinVersion: tls.VersionTLS12,
-				},
-			},
-		}
+		client = meplatoapi.NewDefaultClient()
 	}
 	return &Service{client: client, BaseURL: baseURL}, nil
 }
 
+// NewFromEnv creates a new Service with a default HTTP client. The base
+// URL, user, and password are read from the STORE2_URL, STORE2_USER, and
+// STORE2_PASSWORD environment variables. If STORE2_URL is not set, the
+// default base URL is used. Debug logging is enabled if STORE2_DEBUG is
+// set to true.
+func NewFromEnv() (*Service, error) {
+	s, err := New(meplatoapi.NewDefaultClient())
+	if err != nil {
+		return nil, err
+	}
+	baseURL, user, password := meplatoapi.FromEnv()
+	if baseURL != "" {
+		s.BaseURL = baseURL
+	}
+	s.User = user
+	s.Password = password
+	s.Debug = meplatoapi.DebugFromEnv()
+	return s, nil
+}
+
+// SetMaxConcurrency limits the number of concurrent requests across all
+// operations of the service to n. Requests beyond the limit block until a
+// request finishes or their context is done. If n is less than or equal
+// to zero, the number of requests is not limited (default).
+func (s *Service) SetMaxConcurrency(n int) {
+	s.limiter.SetMax(n)
+}
+
+// WithProxy sends all requests of the service through the given proxy,
+// e.g. http://proxy.example.com:3128, so that services in one process can
+// use different proxies. It replaces the proxy from the HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY environment variables for this service; if
+// proxy is nil, requests are sent directly. The HTTP client of the
+// service is copied, so other users of the client are not affected. It
+// returns an error if the proxy URL is invalid or the client has a custom
+// transport that is not an *http.Transport.
+func (s *Service) WithProxy(proxy *url.URL) error {
+	client, err := meplatoapi.WithProxy(s.client, proxy)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+// InFlight returns the number of requests of the service that are
+// currently in flight.
+func (s *Service) InFlight() int {
+	return s.limiter.InFlight()
+}
+
+// Use adds middleware that wraps every request of the service, e.g. to
+// log, measure, cache, or rewrite requests and responses. Middleware runs
+// in the order it was added, i.e. the first one sees the request first
+// and the response last. It sees the request once, however often it is
+// retried, and the response body is already limited to MaxResponseBytes.
+// Use must not be called concurrently with requests.
+func (s *Service) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// do adds the headers of ContextHeaders to req, attaches the trace
+// created by Trace, and sends it through the middleware added by Use.
+func (s *Service) do(req *http.Request) (*http.Response, error) {
+	meplatoapi.SetContextHeaders(req, s.ContextHeaders)
+	req = meplatoapi.WithTrace(req, s.Trace)
+	return meplatoapi.Chain(s.send, s.middleware)(req)
+}
+
+// send sends req, retrying it according to MaxRetries and respecting the
+// limit set by SetMaxConcurrency. It reports the number of attempts to
+// ObserveAttempts, limits the response body to MaxResponseBytes, and, in
+// debug mode, logs the request and the response.
+func (s *Service) send(req *http.Request) (*http.Response, error) {
+	if s.Debug {
+		if err := meplatoapi.LogRequest(s.Logger, req); err != nil {
+			return nil, err
+		}
+	}
+	var attempts int
+	if s.ObserveAttempts != nil {
+		req = meplatoapi.CountAttempts(req, &attempts)
+	}
+	res, err := s.limiter.Send(s.client, req, s.MaxRetries)
+	if s.ObserveAttempts != nil {
+		s.ObserveAttempts(req, attempts)
+	}
+	if err == nil {
+		if err := meplatoapi.LimitBody(res, s.MaxResponseBytes); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	if err == nil && s.Debug {
+		if err := meplatoapi.LogResponse(s.Logger, res); err != nil {
+			meplatoapi.CloseBody(res)
+			return nil, err
+		}
+	}
+	return res, err
+}
+
 func (s *Service) Me() *MeService {
 	return NewMeService(s)
 }
@@ -197,11 +319,18 @@ func NewMeService(s *Service) *MeService {
 	return rs
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *MeService) WithAuth(user, password string) *MeService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *MeService) Do(ctx context.Context) (*MeResponse, error) {
 	var body io.Reader
 	path := "/"
-	req, err := http.NewRequest("GET", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("GET", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +342,10 @@ func (s *MeService) Do(ctx context.Context) (*MeResponse, error) {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -242,11 +374,18 @@ func NewPingService(s *Service) *PingService {
 	return rs
 }
 
+// WithAuth overrides the user and password of the service for this
+// request only.
+func (s *PingService) WithAuth(user, password string) *PingService {
+	s.hdr_["Authorization"] = meplatoapi.HTTPBasicAuthorizationHeader(user, password)
+	return s
+}
+
 // Do executes the operation.
 func (s *PingService) Do(ctx context.Context) error {
 	var body io.Reader
 	path := "/"
-	req, err := http.NewRequest("HEAD", s.s.BaseURL+path, body)
+	req, err := http.NewRequest("HEAD", meplatoapi.JoinURL(s.s.BaseURL, path), body)
 	if err != nil {
 		return err
 	}
@@ -258,7 +397,10 @@ func (s *PingService) Do(ctx context.Context) error {
 	if s.s.User != "" || s.s.Password != "" {
 		req.Header.Set("Authorization", meplatoapi.HTTPBasicAuthorizationHeader(s.s.User, s.s.Password))
 	}
-	res, err := s.s.client.Do(req)
+	for k, v := range s.hdr_ {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	res, err := s.s.do(req)
 	if err != nil {
 		return err
 	}
@@ -1,72 +1,24 @@
 package store2_test
 
 import (
-	"bufio"
 	"context"
-	"crypto/tls"
-	"fmt"
-	"io/ioutil"
-	"net"
-	"net/http"
 	"net/http/httptest"
-	"os"
 	"path"
-	"runtime"
 	"strings"
 	"testing"
-	"time"
 
 	store2 "github.com/meplato/store2-go-client/v2"
+	"github.com/meplato/store2-go-client/v2/storetest"
 )
 
 func getService(responseFile string) (*store2.Service, *httptest.Server, error) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slurp, err := ioutil.ReadFile(path.Join("testdata", responseFile))
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		res, err := http.ReadResponse(bufio.NewReader(strings.NewReader(string(slurp))), r)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer res.Body.Close()
-		bs, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(res.StatusCode)
-		fmt.Fprint(w, string(bs))
-	}))
-
-	client := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
-			TLSClientConfig: &tls.Config{
-				MinVersion:         tls.VersionTLS12,
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	service, err := store2.New(client)
+	ts := storetest.ReplayServer(path.Join("testdata", responseFile))
+
+	service, err := store2.NewFromEnv()
 	if err != nil {
 		return service, nil, err
 	}
 	service.BaseURL = ts.URL // "http://store2.go/api/v2"
-	service.User = os.Getenv("STORE2_USER")
-	service.Password = os.Getenv("STORE2_PASSWORD")
 	return service, ts, nil
 }
 
@@ -150,3 +102,45 @@ func TestMeUnauthorized(t *testing.T) {
 		t.Errorf("expected error %q; got: %q", "meplatoapi: Error 401: Unauthorized", err.Error())
 	}
 }
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("STORE2_URL", "http://store2.go/api/v2")
+	t.Setenv("STORE2_USER", "user")
+	t.Setenv("STORE2_PASSWORD", "secret")
+
+	service, err := store2.NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.BaseURL != "http://store2.go/api/v2" {
+		t.Errorf("expected BaseURL %q; got: %q", "http://store2.go/api/v2", service.BaseURL)
+	}
+	if service.User != "user" || service.Password != "secret" {
+		t.Errorf("expected user %q and password %q; got: %q and %q", "user", "secret", service.User, service.Password)
+	}
+}
+
+func TestMerchantToken(t *testing.T) {
+	service, ts, err := getService("me.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	token, err := service.MerchantToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "3feeb58c65a4ff1c" {
+		t.Fatalf("expected token %q; got: %q", "3feeb58c65a4ff1c", token)
+	}
+
+	service, ts2, err := getService("me.notoken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts2.Close()
+	if _, err := service.MerchantToken(context.Background()); err != store2.ErrNoMerchantToken {
+		t.Fatalf("expected %v; got: %v", store2.ErrNoMerchantToken, err)
+	}
+}
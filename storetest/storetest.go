@@ -0,0 +1,97 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+// Package storetest provides utilities for testing code that uses the
+// Meplato Store API clients.
+//
+// A fixture is a file with a raw HTTP response as it is sent by the
+// server, including the status line, the headers, and the body, e.g.
+//
+//	HTTP/1.1 200 OK
+//	Content-Type: application/json; charset=utf-8
+//
+//	{"kind": "store#me"}
+//
+// Point a service to the test server by setting its BaseURL, e.g.
+//
+//	ts := storetest.ReplayServer("testdata/me.success")
+//	defer ts.Close()
+//	service, _ := store2.New(nil)
+//	service.BaseURL = ts.URL
+package storetest
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+)
+
+// ReplayServer starts a test server that replies to every request with
+// the response in the fixture file. The caller must close the server.
+func ReplayServer(fixture string) *httptest.Server {
+	return RouteServer(func(r *http.Request) string {
+		return fixture
+	})
+}
+
+// RouteServer starts a test server that replies to each request with the
+// response in the fixture file returned by route. The caller must close
+// the server. If the fixture cannot be read or parsed, the server replies
+// with 500 Internal Server Error.
+func RouteServer(route func(r *http.Request) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Replay(w, r, route(r))
+	}))
+}
+
+// Replay writes the response in the fixture file to w. It can be used to
+// build handlers that do more than just replaying a fixture, e.g. to
+// inspect the request. If the fixture cannot be read or parsed, it
+// replies with 500 Internal Server Error.
+func Replay(w http.ResponseWriter, r *http.Request, fixture string) {
+	res, body, err := ReadFixture(fixture, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for k, v := range res.Header {
+		switch k {
+		case "Content-Length", "Transfer-Encoding", "Connection":
+			// Computed by the test server
+		default:
+			w.Header()[k] = v
+		}
+	}
+	w.WriteHeader(res.StatusCode)
+	w.Write(body)
+}
+
+// ReadFixture parses the response in the fixture file and returns it
+// along with its body. The request r may be nil.
+func ReadFixture(fixture string, r *http.Request) (*http.Response, []byte, error) {
+	slurp, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(slurp)), r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, body, nil
+}
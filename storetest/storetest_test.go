@@ -0,0 +1,63 @@
+package storetest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+	"github.com/meplato/store2-go-client/v2/storetest"
+)
+
+func TestReplayServer(t *testing.T) {
+	ts := storetest.ReplayServer("testdata/me.success")
+	defer ts.Close()
+
+	service, err := store2.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BaseURL = ts.URL
+
+	res, err := service.Me().Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil {
+		t.Fatal("expected response; got: nil")
+	}
+	if res.Kind != "store#me" {
+		t.Fatalf("expected kind %q; got: %q", "store#me", res.Kind)
+	}
+}
+
+func TestRouteServer(t *testing.T) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		if r.URL.Path == "/missing" {
+			return "testdata/does-not-exist"
+		}
+		return "testdata/me.success"
+	})
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d; got: %d", http.StatusOK, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected Content-Type %q; got: %q", "application/json", ct)
+	}
+
+	res, err = http.Get(ts.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected status %d; got: %d", http.StatusInternalServerError, res.StatusCode)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+// Package storeutil provides helpers that combine the services of
+// several packages of the Meplato Store API client.
+package storeutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/meplato/store2-go-client/v2/availabilities"
+	"github.com/meplato/store2-go-client/v2/products"
+)
+
+// ProductDetails combines a product of a catalog with its availability
+// information.
+type ProductDetails struct {
+	// Product is the product as returned by products.GetService.
+	Product *products.Product
+	// Availabilities is the availability information of the product as
+	// returned by availabilities.GetService.
+	Availabilities []*availabilities.Availability
+}
+
+// ProductDetailsError is returned by GetProductService if getting the
+// product or its availabilities fails. The fields of the requests that
+// succeeded are nil.
+type ProductDetailsError struct {
+	// Product is the error of getting the product.
+	Product error
+	// Availabilities is the error of getting the availabilities.
+	Availabilities error
+}
+
+// Error returns the errors of both requests.
+func (e *ProductDetailsError) Error() string {
+	switch {
+	case e.Product != nil && e.Availabilities != nil:
+		return fmt.Sprintf("get product: %v; get availabilities: %v", e.Product, e.Availabilities)
+	case e.Product != nil:
+		return fmt.Sprintf("get product: %v", e.Product)
+	default:
+		return fmt.Sprintf("get availabilities: %v", e.Availabilities)
+	}
+}
+
+// Unwrap returns the error of getting the product, or the error of
+// getting the availabilities if the product was retrieved successfully.
+func (e *ProductDetailsError) Unwrap() error {
+	if e.Product != nil {
+		return e.Product
+	}
+	return e.Availabilities
+}
+
+// GetProductService gets a product and its availabilities in parallel.
+type GetProductService struct {
+	products       *products.Service
+	availabilities *availabilities.Service
+	pin            string
+	area           string
+	spn            string
+	mpcc           string
+	region         string
+	zipCode        string
+}
+
+// GetProduct creates a new GetProductService that uses ps to get the
+// product and as to get its availabilities.
+func GetProduct(ps *products.Service, as *availabilities.Service) *GetProductService {
+	return &GetProductService{products: ps, availabilities: as}
+}
+
+// Area of the catalog, e.g. work or live.
+func (s *GetProductService) Area(area string) *GetProductService {
+	s.area = area
+	return s
+}
+
+// PIN of the catalog.
+func (s *GetProductService) PIN(pin string) *GetProductService {
+	s.pin = pin
+	return s
+}
+
+// Spn is the supplier part number of the product to get.
+func (s *GetProductService) Spn(spn string) *GetProductService {
+	s.spn = spn
+	return s
+}
+
+// Mpcc restricts the availabilities to those of a merchant.
+func (s *GetProductService) Mpcc(mpcc string) *GetProductService {
+	s.mpcc = mpcc
+	return s
+}
+
+// Region restricts the availabilities to a 2-letter ISO country/region code.
+func (s *GetProductService) Region(region string) *GetProductService {
+	s.region = region
+	return s
+}
+
+// ZipCode restricts the availabilities to a zip code.
+func (s *GetProductService) ZipCode(zipCode string) *GetProductService {
+	s.zipCode = zipCode
+	return s
+}
+
+// Do executes the operation. Both requests run to completion, and if any
+// of them fails, the error is a *ProductDetailsError.
+func (s *GetProductService) Do(ctx context.Context) (*ProductDetails, error) {
+	var (
+		wg      sync.WaitGroup
+		ret     ProductDetails
+		derr    ProductDetailsError
+		avail   *availabilities.GetResponse
+		product *products.Product
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		product, derr.Product = s.products.Get().PIN(s.pin).Area(s.area).Spn(s.spn).Do(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		get := s.availabilities.Get().Spn(s.spn)
+		if s.mpcc != "" {
+			get = get.Mpcc(s.mpcc)
+		}
+		if s.region != "" {
+			get = get.Region(s.region)
+		}
+		if s.zipCode != "" {
+			get = get.ZipCode(s.zipCode)
+		}
+		avail, derr.Availabilities = get.Do(ctx)
+	}()
+	wg.Wait()
+
+	if derr.Product != nil || derr.Availabilities != nil {
+		return nil, &derr
+	}
+	ret.Product = product
+	if avail != nil {
+		ret.Availabilities = avail.Items
+	}
+	return &ret, nil
+}
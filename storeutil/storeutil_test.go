@@ -0,0 +1,100 @@
+package storeutil_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/meplato/store2-go-client/v2/availabilities"
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+	"github.com/meplato/store2-go-client/v2/products"
+	"github.com/meplato/store2-go-client/v2/storetest"
+	"github.com/meplato/store2-go-client/v2/storeutil"
+)
+
+func getServices(productFile, availabilitiesFile string) (*products.Service, *availabilities.Service, *httptest.Server, error) {
+	ts := storetest.RouteServer(func(r *http.Request) string {
+		if strings.HasSuffix(r.URL.Path, "/availabilities") {
+			return path.Join("testdata", availabilitiesFile)
+		}
+		return path.Join("testdata", productFile)
+	})
+
+	ps, err := products.NewFromEnv()
+	if err != nil {
+		ts.Close()
+		return nil, nil, nil, err
+	}
+	ps.BaseURL = ts.URL
+	as, err := availabilities.NewFromEnv()
+	if err != nil {
+		ts.Close()
+		return nil, nil, nil, err
+	}
+	as.BaseURL = ts.URL
+	return ps, as, ts, nil
+}
+
+func TestGetProduct(t *testing.T) {
+	ps, as, ts, err := getServices("products.get.success", "availabilities.get.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	res, err := storeutil.GetProduct(ps, as).PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Product == nil {
+		t.Fatal("expected product; got: nil")
+	}
+	if res.Product.Spn != "50763599" {
+		t.Errorf("expected product %q; got: %q", "50763599", res.Product.Spn)
+	}
+	if len(res.Availabilities) != 3 {
+		t.Errorf("expected %d availabilities; got: %d", 3, len(res.Availabilities))
+	}
+}
+
+func TestGetProductErrors(t *testing.T) {
+	tests := []struct {
+		ProductFile        string
+		AvailabilitiesFile string
+		Product            bool
+		Availabilities     bool
+	}{
+		{"products.get.not_found", "availabilities.get.success", true, false},
+		{"products.get.success", "availabilities.get.not_found", false, true},
+		{"products.get.not_found", "availabilities.get.not_found", true, true},
+	}
+	for i, test := range tests {
+		ps, as, ts, err := getServices(test.ProductFile, test.AvailabilitiesFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := storeutil.GetProduct(ps, as).PIN("AD8CCDD5F9").Area("work").Spn("50763599").Do(context.Background())
+		ts.Close()
+		if res != nil {
+			t.Errorf("#%d: expected no response; got: %v", i, res)
+		}
+		var derr *storeutil.ProductDetailsError
+		if !errors.As(err, &derr) {
+			t.Fatalf("#%d: expected ProductDetailsError; got: %v", i, err)
+		}
+		if got := derr.Product != nil; got != test.Product {
+			t.Errorf("#%d: expected product error %v; got: %v", i, test.Product, derr.Product)
+		}
+		if got := derr.Availabilities != nil; got != test.Availabilities {
+			t.Errorf("#%d: expected availabilities error %v; got: %v", i, test.Availabilities, derr.Availabilities)
+		}
+		var apiErr *meplatoapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound {
+			t.Errorf("#%d: expected wrapped not found error; got: %v", i, err)
+		}
+	}
+}
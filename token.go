@@ -0,0 +1,42 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package store2
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoMerchantToken is returned by MerchantToken if the server did not
+// return a shared token for the merchant.
+var ErrNoMerchantToken = errors.New("store2: no merchant token")
+
+// MerchantToken returns the shared token of the merchant of the
+// authenticated user, as returned by Me. The services of the other
+// packages authenticate with the token if it is set as their User, e.g.
+//
+//	token, err := store2Service.MerchantToken(ctx)
+//	...
+//	service.User, service.Password = token, "" // e.g. a *products.Service
+//
+// Only pass the token to endpoints that accept the shared merchant token.
+func (s *Service) MerchantToken(ctx context.Context) (string, error) {
+	me, err := s.Me().Do(ctx)
+	if err != nil {
+		return "", err
+	}
+	if me.Merchant == nil || me.Merchant.Token == "" {
+		return "", ErrNoMerchantToken
+	}
+	return me.Merchant.Token, nil
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2013-present Meplato GmbH.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under
+// the License.
+
+package store2
+
+import (
+	"net/http"
+	"net/http/httptrace"
+
+	"github.com/meplato/store2-go-client/v2/internal/meplatoapi"
+)
+
+// Timings are the durations of the phases of a request, e.g. DNS lookup,
+// connecting, the TLS handshake, and the time to the first byte.
+type Timings = meplatoapi.Timings
+
+// TimingTrace returns a function suitable for the Trace field of all
+// services. It measures the Timings of every request and passes them to
+// observe, e.g. to record them in your metrics.
+//
+//	service.Trace = store2.TimingTrace(func(req *http.Request, t store2.Timings) {
+//		log.Printf("%s %s: ttfb=%v", req.Method, req.URL.Path, t.TTFB)
+//	})
+func TimingTrace(observe func(req *http.Request, t Timings)) func(req *http.Request) *httptrace.ClientTrace {
+	return meplatoapi.TimingTrace(observe)
+}
@@ -0,0 +1,73 @@
+package store2_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"testing"
+
+	store2 "github.com/meplato/store2-go-client/v2"
+)
+
+func TestTimingTrace(t *testing.T) {
+	service, ts, err := getService("me.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var observed []store2.Timings
+	var paths []string
+	service.Trace = store2.TimingTrace(func(req *http.Request, timings store2.Timings) {
+		mu.Lock()
+		defer mu.Unlock()
+		observed = append(observed, timings)
+		paths = append(paths, req.URL.Path)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.Me().Do(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(observed) != 2 {
+		t.Fatalf("expected %d observations; got: %d", 2, len(observed))
+	}
+	for i, timings := range observed {
+		if timings.TTFB <= 0 {
+			t.Errorf("#%d: expected TTFB > 0; got: %v", i, timings.TTFB)
+		}
+		if timings.TTFB < timings.DNS+timings.Connect+timings.TLS {
+			t.Errorf("#%d: expected TTFB to include the connection phases; got: %+v", i, timings)
+		}
+		if paths[i] != "/" {
+			t.Errorf("#%d: expected path %q; got: %q", i, "/", paths[i])
+		}
+	}
+}
+
+func TestTraceIsAttachedToRequests(t *testing.T) {
+	service, ts, err := getService("me.success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	var gotConn bool
+	service.Trace = func(req *http.Request) *httptrace.ClientTrace {
+		return &httptrace.ClientTrace{
+			GotConn: func(httptrace.GotConnInfo) { gotConn = true },
+		}
+	}
+	if _, err := service.Me().Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !gotConn {
+		t.Fatal("expected trace to be called")
+	}
+}